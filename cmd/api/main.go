@@ -3,11 +3,13 @@ package main
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/targc/kontrol/pkg/api"
 	"github.com/targc/kontrol/pkg/config"
 	"github.com/targc/kontrol/pkg/database"
+	"github.com/targc/kontrol/pkg/manager"
 )
 
 func main() {
@@ -29,11 +31,25 @@ func main() {
 		}
 	}
 
+	if cfg.AdminBootstrapToken != "" {
+		err = manager.NewAdminTokenManager(db).EnsureBootstrap(ctx, cfg.AdminBootstrapToken)
+
+		if err != nil {
+			log.Fatalf("failed to bootstrap admin token: %v", err)
+		}
+	}
+
 	app := fiber.New()
 
-	server := api.NewServer(db)
+	server := api.NewServer(db, cfg.AuthCacheTTL, cfg.AuthCacheSize, api.RetryPolicy{
+		BackoffBase: cfg.RetryBackoffBase,
+		BackoffMax:  cfg.RetryBackoffMax,
+		Budget:      cfg.RetryBudget,
+	}, cfg.SelfHealInterval)
 	server.SetupRoutes(app)
 
+	go listenForChanges(ctx, server, cfg.DBURL)
+
 	log.Printf("Starting API server on port %s", cfg.ServerPort)
 
 	err = app.Listen(":" + cfg.ServerPort)
@@ -42,3 +58,18 @@ func main() {
 		log.Fatalf("failed to start server: %v", err)
 	}
 }
+
+// listenForChanges keeps the Postgres change listener running, reconnecting after failures.
+// Workers fall back to polling while it is down.
+func listenForChanges(ctx context.Context, server *api.Server, dbURL string) {
+	for {
+		err := server.ListenForChanges(ctx, dbURL)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Printf("change listener stopped, reconnecting in 5s: %v", err)
+		time.Sleep(5 * time.Second)
+	}
+}
@@ -22,21 +22,34 @@ func main() {
 	cfg := config.LoadWorkerConfig(ctx)
 
 	k8s.InitSupportedGVRs(cfg.SupportedGVRs)
-	log.Printf("Watching %d GVRs", len(k8s.SupportedGVRs))
+	log.Printf("Watching %d GVRs", len(k8s.SupportedGVRs)+len(k8s.SupportedResourceArgs))
 
 	client := apiclient.NewClient(cfg.APIURL, cfg.APIKey, cfg.ClusterID)
 
-	w, err := worker.NewWorker(ctx, client, cfg.ClusterID, cfg.Kubeconfig)
+	w, err := worker.NewWorker(ctx, client, cfg)
 
 	if err != nil {
 		log.Fatalf("Failed to create worker: %v", err)
 	}
 
-	go w.Start(ctx)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- w.Start(ctx)
+	}()
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	<-sigChan
 	w.Stop()
+
+	// Wait for in-flight applies to be recorded before exiting
+	err = <-done
+
+	if err != nil {
+		log.Fatalf("Worker did not shut down cleanly: %v", err)
+	}
+
+	log.Println("Worker shut down cleanly")
 }
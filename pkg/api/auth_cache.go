@@ -0,0 +1,127 @@
+package api
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// apiKeyCache remembers successful cluster API key verifications so that
+// AuthMiddleware does not have to run bcrypt on every worker request. The admin
+// middleware uses a separate instance for admin tokens, with an empty cluster ID.
+// Entries are keyed by cluster ID and a SHA-256 digest of the presented key,
+// so plaintext keys are never held in memory beyond the request.
+type apiKeyCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+}
+
+type apiKeyCacheEntry struct {
+	cacheKey  string
+	keyID     uuid.UUID
+	expiresAt time.Time
+}
+
+func newAPIKeyCache(ttl time.Duration, maxEntries int) *apiKeyCache {
+	return &apiKeyCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+func apiKeyCacheKey(clusterID, apiKey string) string {
+	digest := sha256.Sum256([]byte(apiKey))
+	return clusterID + ":" + hex.EncodeToString(digest[:])
+}
+
+// Get returns the ID of the key when it was verified for the cluster and has not expired
+func (c *apiKeyCache) Get(clusterID, apiKey string) (uuid.UUID, bool) {
+	if c.ttl <= 0 {
+		return uuid.Nil, false
+	}
+
+	cacheKey := apiKeyCacheKey(clusterID, apiKey)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[cacheKey]
+
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	entry := elem.Value.(*apiKeyCacheEntry)
+
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		return uuid.Nil, false
+	}
+
+	c.lru.MoveToFront(elem)
+
+	return entry.keyID, true
+}
+
+// Add records a successful verification. keyExpiresAt caps the entry lifetime
+// so that rotated keys stop being accepted at the end of their overlap window.
+func (c *apiKeyCache) Add(clusterID, apiKey string, keyID uuid.UUID, keyExpiresAt *time.Time) {
+	if c.ttl <= 0 || c.maxEntries <= 0 {
+		return
+	}
+
+	expiresAt := time.Now().Add(c.ttl)
+
+	if keyExpiresAt != nil && keyExpiresAt.Before(expiresAt) {
+		expiresAt = *keyExpiresAt
+	}
+
+	cacheKey := apiKeyCacheKey(clusterID, apiKey)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[cacheKey]; ok {
+		c.removeElement(elem)
+	}
+
+	for c.lru.Len() >= c.maxEntries {
+		c.removeElement(c.lru.Back())
+	}
+
+	c.entries[cacheKey] = c.lru.PushFront(&apiKeyCacheEntry{
+		cacheKey:  cacheKey,
+		keyID:     keyID,
+		expiresAt: expiresAt,
+	})
+}
+
+// InvalidateKey drops every cached verification of the given API key
+func (c *apiKeyCache) InvalidateKey(keyID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+
+		if elem.Value.(*apiKeyCacheEntry).keyID == keyID {
+			c.removeElement(elem)
+		}
+
+		elem = next
+	}
+}
+
+func (c *apiKeyCache) removeElement(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*apiKeyCacheEntry).cacheKey)
+}
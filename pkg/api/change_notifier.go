@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/targc/kontrol/pkg/database"
+)
+
+// Change event types pushed to workers
+const (
+	ChangeEventResource       = "resource"
+	ChangeEventGlobalResource = "global-resource"
+	ChangeEventResync         = "resync"    // notifications may have been missed, re-check everything
+	ChangeEventHeartbeat      = "heartbeat" // the API is listening for changes, so events will arrive
+)
+
+// ChangeEvent tells a worker that a resource of its cluster or a global resource changed
+type ChangeEvent struct {
+	Type string    `json:"type"`
+	ID   uuid.UUID `json:"id"`
+}
+
+// changeNotification is the payload sent by the generation triggers
+type changeNotification struct {
+	Table     string    `json:"table"`
+	ID        uuid.UUID `json:"id"`
+	ClusterID string    `json:"cluster_id"`
+}
+
+// changeNotifier fans change events out to the event streams of connected workers.
+// Events are wake-up hints, not a log: a subscriber whose buffer is full already has a
+// pending wake-up, so further events are dropped rather than blocking the listener.
+type changeNotifier struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan ChangeEvent]struct{}
+	listening   atomic.Bool // whether the Postgres listener is connected
+}
+
+func newChangeNotifier() *changeNotifier {
+	return &changeNotifier{
+		subscribers: make(map[string]map[chan ChangeEvent]struct{}),
+	}
+}
+
+func (n *changeNotifier) subscribe(clusterID string) chan ChangeEvent {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	ch := make(chan ChangeEvent, 16)
+
+	if n.subscribers[clusterID] == nil {
+		n.subscribers[clusterID] = make(map[chan ChangeEvent]struct{})
+	}
+
+	n.subscribers[clusterID][ch] = struct{}{}
+
+	return ch
+}
+
+func (n *changeNotifier) unsubscribe(clusterID string, ch chan ChangeEvent) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	delete(n.subscribers[clusterID], ch)
+
+	if len(n.subscribers[clusterID]) == 0 {
+		delete(n.subscribers, clusterID)
+	}
+}
+
+// publish sends event to the subscribers of clusterID, or to every subscriber when
+// clusterID is empty
+func (n *changeNotifier) publish(clusterID string, event ChangeEvent) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for id, subscribers := range n.subscribers {
+		if clusterID != "" && id != clusterID {
+			continue
+		}
+
+		for ch := range subscribers {
+			select {
+			case ch <- event:
+			default:
+			}
+		}
+	}
+}
+
+// ListenForChanges listens on the Postgres channel fed by the generation triggers and
+// pushes a change event to the workers of the affected cluster, or to every worker for
+// global resources. It blocks until ctx is cancelled or the connection fails; callers
+// are expected to call it again. Workers are told to resync once listening starts, since
+// notifications sent while the listener was down are lost, and event streams only send
+// heartbeats while it is listening.
+func (s *Server) ListenForChanges(ctx context.Context, dbURL string) error {
+	conn, err := pgx.Connect(ctx, dbURL)
+
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, "LISTEN "+database.ChangesChannel)
+
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	s.changeNotifier.listening.Store(true)
+	defer s.changeNotifier.listening.Store(false)
+
+	s.changeNotifier.publish("", ChangeEvent{Type: ChangeEventResync})
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to wait for notification: %w", err)
+		}
+
+		var payload changeNotification
+
+		if err := json.Unmarshal([]byte(notification.Payload), &payload); err != nil {
+			continue
+		}
+
+		switch payload.Table {
+		case "k_resources":
+			s.changeNotifier.publish(payload.ClusterID, ChangeEvent{Type: ChangeEventResource, ID: payload.ID})
+		case "k_global_resources":
+			s.changeNotifier.publish("", ChangeEvent{Type: ChangeEventGlobalResource, ID: payload.ID})
+		case "k_clusters":
+			// Cluster labels changed, so the global resources targeting it may have too
+			s.changeNotifier.publish(payload.ClusterID, ChangeEvent{Type: ChangeEventGlobalResource})
+		}
+	}
+}
@@ -1,7 +1,11 @@
 package api
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/manager"
+	"github.com/targc/kontrol/pkg/models"
 	"gorm.io/gorm"
 )
 
@@ -10,19 +14,99 @@ type ErrorResponse struct {
 }
 
 type Server struct {
-	db *gorm.DB
+	db                    *gorm.DB
+	resourceManager       *manager.ResourceManager
+	globalResourceManager *manager.GlobalResourceManager
+	adminTokenManager     *manager.AdminTokenManager
+	clusterManager        *manager.ClusterManager
+	apiKeyCache           *apiKeyCache
+	adminTokenCache       *apiKeyCache
+	changeNotifier        *changeNotifier
+	retryPolicy           RetryPolicy
+	selfHealInterval      time.Duration
 }
 
-func NewServer(db *gorm.DB) *Server {
-	return &Server{db: db}
+// NewServer creates a new Server. Verified cluster API keys and admin tokens are cached for
+// authCacheTTL, up to authCacheSize entries; a zero TTL disables the cache.
+// retryPolicy decides when resources whose apply failed are handed out again, and
+// self-healing resources are re-applied at most once per selfHealInterval.
+func NewServer(db *gorm.DB, authCacheTTL time.Duration, authCacheSize int, retryPolicy RetryPolicy, selfHealInterval time.Duration) *Server {
+	return &Server{
+		db:                    db,
+		resourceManager:       manager.NewResourceManager(db),
+		globalResourceManager: manager.NewGlobalResourceManager(db),
+		adminTokenManager:     manager.NewAdminTokenManager(db),
+		clusterManager:        manager.NewClusterManager(db),
+		apiKeyCache:           newAPIKeyCache(authCacheTTL, authCacheSize),
+		adminTokenCache:       newAPIKeyCache(authCacheTTL, authCacheSize),
+		changeNotifier:        newChangeNotifier(),
+		retryPolicy:           retryPolicy,
+		selfHealInterval:      selfHealInterval,
+	}
 }
 
 func (s *Server) SetupRoutes(app *fiber.App) {
+	app.Get("/health", s.Health)
+
+	// Public API for operators and CI, authenticated with admin tokens
+	pub := app.Group("/api/v1", s.AdminAuthMiddleware())
+
+	read := s.RequireScope(models.AdminScopeReadOnly)
+	write := s.RequireScope(models.AdminScopeWrite)
+	admin := s.RequireScope(models.AdminScopeAdmin)
+
+	// Resources
+	pub.Post("/resources", write, s.PublicCreateResource)
+	pub.Put("/resources", write, s.PublicUpsertResource)
+	pub.Get("/resources", read, s.PublicListResources)
+	pub.Get("/resources/by-key", read, s.PublicGetResourceByKey)
+	pub.Get("/resources/drifted", read, s.PublicListDriftedResources)
+	pub.Get("/resources/:id", read, s.PublicGetResource)
+	pub.Put("/resources/:id", write, s.PublicUpdateResource)
+	pub.Delete("/resources/:id", write, s.PublicDeleteResource)
+	pub.Get("/resources/:id/revisions", read, s.PublicListResourceRevisions)
+	pub.Post("/resources/:id/rollback", write, s.PublicRollbackResource)
+	pub.Put("/resources/:id/self-heal", write, s.PublicSetResourceSelfHeal)
+
+	// Global resources
+	pub.Post("/global-resources", write, s.PublicCreateGlobalResource)
+	pub.Put("/global-resources", write, s.PublicUpsertGlobalResource)
+	pub.Get("/global-resources", read, s.PublicListGlobalResources)
+	pub.Get("/global-resources/:id", read, s.PublicGetGlobalResource)
+	pub.Put("/global-resources/:id", write, s.PublicUpdateGlobalResource)
+	pub.Delete("/global-resources/:id", write, s.PublicDeleteGlobalResource)
+	pub.Get("/global-resources/:id/revisions", read, s.PublicListGlobalResourceRevisions)
+	pub.Post("/global-resources/:id/rollback", write, s.PublicRollbackGlobalResource)
+	pub.Put("/global-resources/:id/self-heal", write, s.PublicSetGlobalResourceSelfHeal)
+	pub.Put("/global-resources/:id/cluster-selector", write, s.PublicSetGlobalResourceClusterSelector)
+	pub.Put("/global-resources/:id/overrides", write, s.PublicSetGlobalResourceOverrides)
+	pub.Put("/global-resources/:id/rollout-strategy", write, s.PublicSetGlobalResourceRolloutStrategy)
+
+	// Clusters and worker API keys
+	pub.Post("/clusters", admin, s.PublicCreateCluster)
+	pub.Get("/clusters", read, s.PublicListClusters)
+	pub.Get("/clusters/:id", read, s.PublicGetCluster)
+	pub.Put("/clusters/:id/labels", admin, s.PublicSetClusterLabels)
+	pub.Post("/clusters/:id/api-keys", admin, s.PublicCreateClusterAPIKey)
+	pub.Get("/clusters/:id/api-keys", admin, s.PublicListClusterAPIKeys)
+	pub.Delete("/clusters/:id/api-keys/:key_id", admin, s.PublicRevokeClusterAPIKey)
+	pub.Post("/clusters/:id/api-keys/:key_id/rotate", admin, s.PublicRotateClusterAPIKey)
+
+	// Admin tokens
+	pub.Post("/admin-tokens", admin, s.PublicCreateAdminToken)
+	pub.Get("/admin-tokens", admin, s.PublicListAdminTokens)
+	pub.Delete("/admin-tokens/:id", admin, s.PublicRevokeAdminToken)
+
 	// Internal API for workers
 	int := app.Group("/int/api/v1", s.AuthMiddleware())
 
 	// Cluster registration
 	int.Post("/cluster/register", s.RegisterCluster)
+	int.Post("/cluster/lease", s.AcquireLease)
+	int.Delete("/cluster/lease", s.ReleaseLease)
+
+	// Change events (for reconciler and global syncer)
+	int.Get("/events", s.StreamEvents)
 
 	// Resources (for reconciler)
 	int.Get("/resources/out-of-sync", s.ListOutOfSyncResources)
@@ -31,16 +115,18 @@ func (s *Server) SetupRoutes(app *fiber.App) {
 	int.Delete("/resources/:id", s.HardDeleteResource)
 
 	// Resources (for watcher)
+	int.Get("/resources/current-states", s.ListCurrentStateResources)
 	int.Post("/resources/:id/current-state", s.UpsertCurrentState)
 	int.Delete("/resources/:id/current-state", s.DeleteCurrentState)
 
 	// Resources (for global syncer)
-	int.Post("/resources", s.CreateResource)
-	int.Delete("/resources/by-key", s.SoftDeleteResourceByKey)
+	int.Post("/resources", s.UpsertResource)
 
 	// Global resources (for global syncer)
 	int.Get("/global-resources/out-of-sync", s.ListOutOfSyncGlobalResources)
 	int.Get("/global-resources/deleted", s.ListDeletedGlobalResources)
+	int.Get("/global-resources/unmatched", s.ListUnmatchedGlobalResources)
 	int.Post("/global-resources/:id/synced-state", s.UpsertSyncedState)
 	int.Delete("/global-resources/:id/synced-state", s.DeleteSyncedState)
+	int.Delete("/global-resources/:id/resource", s.DeleteDerivedResource)
 }
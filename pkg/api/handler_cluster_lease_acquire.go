@@ -0,0 +1,72 @@
+package api
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/models"
+)
+
+type AcquireLeaseRequest struct {
+	HolderID        string `json:"holder_id"`
+	DurationSeconds int    `json:"duration_seconds"`
+}
+
+type LeaseResponse struct {
+	Acquired  bool       `json:"acquired"`
+	HolderID  string     `json:"holder_id"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type AcquireLeaseResponse struct {
+	Data LeaseResponse `json:"data"`
+}
+
+// AcquireLease acquires or renews the leader lease of the cluster for the calling worker.
+// The lease is granted when it is free, expired or already held by the caller; expiry is
+// computed from the database clock so worker clock skew does not matter.
+func (s *Server) AcquireLease(c fiber.Ctx) error {
+	clusterID := c.Locals("cluster_id").(string)
+	ctx := c.Context()
+
+	var req AcquireLeaseRequest
+
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+	}
+
+	if req.HolderID == "" || req.DurationSeconds <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "holder_id and a positive duration_seconds are required"})
+	}
+
+	result := s.db.
+		WithContext(ctx).
+		Exec(`
+			UPDATE k_clusters
+			SET leader_id = ?, leader_lease_expires_at = NOW() + make_interval(secs => ?)
+			WHERE id = ?
+			  AND (leader_id = ? OR leader_id = '' OR leader_lease_expires_at IS NULL OR leader_lease_expires_at < NOW())
+		`, req.HolderID, req.DurationSeconds, clusterID, req.HolderID)
+
+	if result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to acquire lease"})
+	}
+
+	var cluster models.Cluster
+
+	err := s.db.
+		WithContext(ctx).
+		Where("id = ?", clusterID).
+		First(&cluster).
+		Error
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to query lease"})
+	}
+
+	return c.JSON(AcquireLeaseResponse{Data: LeaseResponse{
+		Acquired:  result.RowsAffected == 1,
+		HolderID:  cluster.LeaderID,
+		ExpiresAt: cluster.LeaderLeaseExpiresAt,
+	}})
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v3"
+)
+
+type ReleaseLeaseRequest struct {
+	HolderID string `json:"holder_id"`
+}
+
+type ReleaseLeaseResponse struct {
+	Success bool `json:"success"`
+}
+
+// ReleaseLease gives up the leader lease of the cluster if the caller holds it, so a
+// standby can take over without waiting for the lease to expire
+func (s *Server) ReleaseLease(c fiber.Ctx) error {
+	clusterID := c.Locals("cluster_id").(string)
+	ctx := c.Context()
+
+	var req ReleaseLeaseRequest
+
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+	}
+
+	err := s.db.
+		WithContext(ctx).
+		Exec("UPDATE k_clusters SET leader_id = '', leader_lease_expires_at = NULL WHERE id = ? AND leader_id = ?", clusterID, req.HolderID).
+		Error
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to release lease"})
+	}
+
+	return c.JSON(ReleaseLeaseResponse{Success: true})
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// eventHeartbeatInterval keeps idle event streams alive through proxies and lets both
+// sides notice a dead connection
+const eventHeartbeatInterval = 15 * time.Second
+
+// StreamEvents pushes change events of the calling cluster as Server-Sent Events until
+// the worker disconnects. Each event is named after its type and carries the
+// ChangeEvent as JSON data. Heartbeat events are only sent while the Postgres listener is
+// connected, so workers can tell a live stream from one that will not deliver changes;
+// otherwise a comment line keeps the connection open.
+func (s *Server) StreamEvents(c fiber.Ctx) error {
+	clusterID := c.Locals("cluster_id").(string)
+	events := s.changeNotifier.subscribe(clusterID)
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		defer s.changeNotifier.unsubscribe(clusterID, events)
+
+		heartbeat := time.NewTicker(eventHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		fmt.Fprint(w, ": connected\n\n")
+
+		if s.changeNotifier.listening.Load() {
+			writeChangeEvent(w, ChangeEvent{Type: ChangeEventHeartbeat})
+		}
+
+		// A failed flush means the worker went away
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case event := <-events:
+				writeChangeEvent(w, event)
+			case <-heartbeat.C:
+				if s.changeNotifier.listening.Load() {
+					writeChangeEvent(w, ChangeEvent{Type: ChangeEventHeartbeat})
+				} else {
+					fmt.Fprint(w, ": listener down\n\n")
+				}
+			}
+
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+}
+
+func writeChangeEvent(w *bufio.Writer, event ChangeEvent) {
+	data, _ := json.Marshal(event)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/models"
+)
+
+type DeleteDerivedResourceResponse struct {
+	Success bool `json:"success"`
+}
+
+// DeleteDerivedResource soft-deletes the resource derived from a global resource in the
+// calling cluster. It matches by link, so a local resource with the same key is kept.
+func (s *Server) DeleteDerivedResource(c fiber.Ctx) error {
+	clusterID := c.Locals("cluster_id").(string)
+	ctx := c.Context()
+	globalResourceID, err := uuid.Parse(c.Params("id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid global resource id"})
+	}
+
+	err = s.db.
+		WithContext(ctx).
+		Where("cluster_id = ? AND global_resource_id = ?", clusterID, globalResourceID).
+		Delete(&models.Resource{}).
+		Error
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to delete resource"})
+	}
+
+	return c.JSON(DeleteDerivedResourceResponse{Success: true})
+}
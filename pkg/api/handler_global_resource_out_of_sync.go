@@ -1,11 +1,15 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/manager"
+	"github.com/targc/kontrol/pkg/models"
 )
 
 type GlobalResourceForSync struct {
@@ -17,8 +21,24 @@ type GlobalResourceForSync struct {
 	DesiredSpec json.RawMessage `json:"desired_spec"`
 	Generation  int             `json:"generation"`
 	Revision    int             `json:"revision"`
+	SelfHeal    bool            `json:"self_heal"`
+
+	Overrides        []models.ClusterOverride `gorm:"serializer:json" json:"-"` // already applied to DesiredSpec
+	RolloutStrategy  *models.RolloutStrategy  `gorm:"serializer:json" json:"-"`
+	SyncedGeneration *int                     `json:"-"` // nil when never synced to this cluster
+	CreatedAt        time.Time                `json:"-"`
+
+	ClusterLabels map[string]string `gorm:"-" json:"cluster_labels"` // the labels DesiredSpec was derived from, echoed back in the synced state
 }
 
+// syncConflictRetryInterval is how long a global resource is held back from a cluster after
+// its derived resource conflicted with a local resource of the same key
+const syncConflictRetryInterval = 5 * time.Minute
+
+// outOfSyncMaxPages bounds how many pages of candidates one request scans while rollout
+// gating holds candidates back
+const outOfSyncMaxPages = 4
+
 type ListOutOfSyncGlobalResourcesResponse struct {
 	Data []GlobalResourceForSync `json:"data"`
 }
@@ -35,29 +55,156 @@ func (s *Server) ListOutOfSyncGlobalResources(c fiber.Ctx) error {
 		limit = 500
 	}
 
+	resources := []GlobalResourceForSync{}
+
+	// Candidates are read in pages and rollout gating is evaluated per page, so held
+	// resources cannot crowd out the rest while one request stays bounded
+	var cursor outOfSyncCursor
+
+	for page := 0; page < outOfSyncMaxPages && len(resources) < limit; page++ {
+		candidates, err := s.outOfSyncGlobalResources(ctx, clusterID, cursor, limit)
+
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to list global resources"})
+		}
+
+		full := len(candidates) == limit
+
+		if full {
+			last := candidates[len(candidates)-1]
+			cursor = outOfSyncCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		}
+
+		admitted, err := s.admitRollouts(ctx, clusterID, candidates)
+
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to get rollout status"})
+		}
+
+		resources = append(resources, admitted[:min(len(admitted), limit-len(resources))]...)
+
+		if !full {
+			break
+		}
+	}
+
+	if len(resources) == 0 {
+		return c.JSON(ListOutOfSyncGlobalResourcesResponse{Data: resources})
+	}
+
+	cluster, err := s.clusterManager.Get(ctx, clusterID)
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to get cluster"})
+	}
+
+	// Derive this cluster's spec from the shared base
+	for i := range resources {
+		spec, err := manager.ApplyOverrides(resources[i].DesiredSpec, resources[i].Overrides, cluster)
+
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to apply overrides"})
+		}
+
+		resources[i].DesiredSpec = spec
+		resources[i].ClusterLabels = cluster.Labels
+	}
+
+	return c.JSON(ListOutOfSyncGlobalResourcesResponse{Data: resources})
+}
+
+// outOfSyncCursor is the position after the last candidate of a page; the zero value
+// starts at the beginning
+type outOfSyncCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// outOfSyncGlobalResources returns up to limit global resources after cursor that target
+// the cluster and need syncing there: never synced, synced at an older generation, synced
+// but the derived resource is gone (deleted, or lost to a cleanup racing a selector or
+// label change), or with overrides evaluated against labels the cluster no longer has.
+// Ones whose derived resource recently conflicted with a local resource are backed off.
+func (s *Server) outOfSyncGlobalResources(ctx context.Context, clusterID string, cursor outOfSyncCursor, limit int) ([]GlobalResourceForSync, error) {
 	var resources []GlobalResourceForSync
 
-	// Global resources where synced_generation < generation OR synced_state doesn't exist for this cluster
 	err := s.db.
 		WithContext(ctx).
 		Raw(`
-			SELECT gr.id, gr.namespace, gr.kind, gr.name, gr.api_version, gr.desired_spec, gr.generation, gr.revision
+			SELECT gr.id, gr.namespace, gr.kind, gr.name, gr.api_version, gr.desired_spec, gr.generation, gr.revision, gr.self_heal, gr.overrides, gr.rollout_strategy,
+				gr.created_at, ss.synced_generation
 			FROM k_global_resources gr
+			JOIN k_clusters c ON c.id = ?
 			LEFT JOIN k_global_resource_synced_states ss
 				ON gr.id = ss.global_resource_id
-				AND ss.cluster_id = ?
+				AND ss.cluster_id = c.id
 				AND ss.deleted_at IS NULL
 			WHERE gr.deleted_at IS NULL
-			AND (ss.id IS NULL OR ss.synced_generation < gr.generation)
-			ORDER BY gr.created_at ASC
+			AND (gr.created_at, gr.id) > (?, ?)
+			AND kontrol_selector_matches(gr.cluster_selector, c.labels)
+			AND (
+				ss.id IS NULL
+				OR ss.synced_generation < gr.generation
+				OR (
+					jsonb_typeof(gr.overrides) = 'array'
+					AND gr.overrides <> '[]'::jsonb
+					AND ss.cluster_labels IS DISTINCT FROM c.labels
+				)
+				OR NOT EXISTS (
+					SELECT 1 FROM k_resources r
+					WHERE r.global_resource_id = gr.id
+					AND r.cluster_id = c.id
+					AND r.deleted_at IS NULL
+				)
+			)
+			AND (ss.conflict_at IS NULL OR ss.conflict_at <= ?)
+			ORDER BY gr.created_at ASC, gr.id ASC
 			LIMIT ?
-		`, clusterID, limit).
+		`, clusterID, cursor.CreatedAt, cursor.ID, time.Now().Add(-syncConflictRetryInterval), limit).
 		Scan(&resources).
 		Error
 
+	return resources, err
+}
+
+// admitRollouts drops the candidates whose rollout has not reached the cluster yet,
+// evaluating the rollouts of the whole page at once. Re-deriving a generation the cluster
+// already synced is not held back.
+func (s *Server) admitRollouts(ctx context.Context, clusterID string, candidates []GlobalResourceForSync) ([]GlobalResourceForSync, error) {
+	var gatedIDs []uuid.UUID
+
+	for _, resource := range candidates {
+		if rolloutGated(&resource) {
+			gatedIDs = append(gatedIDs, resource.ID)
+		}
+	}
+
+	rollouts, err := s.globalResourceManager.Rollouts(ctx, gatedIDs)
+
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to list global resources"})
+		return nil, err
 	}
 
-	return c.JSON(ListOutOfSyncGlobalResourcesResponse{Data: resources})
+	admitted := candidates[:0]
+
+	for _, resource := range candidates {
+		if rolloutGated(&resource) {
+			// Missing when deleted or changed since it was listed; picked up on the next poll
+			rollout, ok := rollouts[resource.ID]
+
+			if !ok || !rollout.Admits(clusterID) {
+				continue
+			}
+		}
+
+		admitted = append(admitted, resource)
+	}
+
+	return admitted, nil
+}
+
+// rolloutGated reports whether a global resource's rollout strategy decides when this
+// cluster picks up its current generation
+func rolloutGated(resource *GlobalResourceForSync) bool {
+	return resource.RolloutStrategy != nil && (resource.SyncedGeneration == nil || *resource.SyncedGeneration < resource.Generation)
 }
@@ -9,7 +9,8 @@ import (
 )
 
 type UpsertSyncedStateRequest struct {
-	SyncedGeneration int `json:"synced_generation"`
+	SyncedGeneration int               `json:"synced_generation"`
+	ClusterLabels    map[string]string `json:"cluster_labels"` // the cluster labels the synced spec was derived from
 }
 
 type UpsertSyncedStateResponse struct {
@@ -48,6 +49,7 @@ func (s *Server) UpsertSyncedState(c fiber.Ctx) error {
 			GlobalResourceID: globalResourceID,
 			ClusterID:        clusterID,
 			SyncedGeneration: req.SyncedGeneration,
+			ClusterLabels:    req.ClusterLabels,
 		}
 
 		if err := tx.Create(&syncedState).Error; err != nil {
@@ -56,9 +58,14 @@ func (s *Server) UpsertSyncedState(c fiber.Ctx) error {
 	} else if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to query synced state"})
 	} else {
+		syncedState.SyncedGeneration = req.SyncedGeneration
+		syncedState.ClusterLabels = req.ClusterLabels
+		syncedState.Conflict = ""
+		syncedState.ConflictAt = nil
+
 		err = tx.
-			Model(&syncedState).
-			Update("synced_generation", req.SyncedGeneration).
+			Select("synced_generation", "cluster_labels", "conflict", "conflict_at").
+			Updates(&syncedState).
 			Error
 
 		if err != nil {
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/models"
+)
+
+type ListUnmatchedGlobalResourcesResponse struct {
+	Data []models.GlobalResource `json:"data"`
+}
+
+// ListUnmatchedGlobalResources lists global resources synced to the calling cluster whose
+// cluster selector no longer matches it, so the worker can remove the derived resources
+func (s *Server) ListUnmatchedGlobalResources(c fiber.Ctx) error {
+	clusterID := c.Locals("cluster_id").(string)
+	ctx := c.Context()
+
+	limit := 100
+	if l, err := strconv.Atoi(c.Query("limit", "100")); err == nil && l > 0 {
+		limit = l
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	var resources []models.GlobalResource
+
+	err := s.db.
+		WithContext(ctx).
+		Raw(`
+			SELECT gr.*
+			FROM k_global_resources gr
+			JOIN k_global_resource_synced_states ss
+				ON gr.id = ss.global_resource_id
+				AND ss.cluster_id = ?
+				AND ss.deleted_at IS NULL
+			JOIN k_clusters c ON c.id = ss.cluster_id
+			WHERE gr.deleted_at IS NULL
+			AND NOT kontrol_selector_matches(gr.cluster_selector, c.labels)
+			ORDER BY gr.created_at ASC
+			LIMIT ?
+		`, clusterID, limit).
+		Scan(&resources).
+		Error
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to list unmatched global resources"})
+	}
+
+	return c.JSON(ListUnmatchedGlobalResourcesResponse{Data: resources})
+}
@@ -0,0 +1,13 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v3"
+)
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+func (s *Server) Health(c fiber.Ctx) error {
+	return c.JSON(HealthResponse{Status: "healthy"})
+}
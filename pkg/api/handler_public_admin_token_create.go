@@ -0,0 +1,36 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicCreateAdminTokenResponse struct {
+	Data *manager.AdminTokenWithSecret `json:"data"`
+}
+
+func (s *Server) PublicCreateAdminToken(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	var req manager.CreateAdminTokenRequest
+
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+	}
+
+	if req.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "name is required"})
+	}
+
+	adminToken, err := s.adminTokenManager.Create(ctx, req)
+
+	if errors.Is(err, manager.ErrInvalidAdminScope) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "scope must be one of read-only, write, admin"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to create admin token"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(PublicCreateAdminTokenResponse{Data: adminToken})
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/models"
+)
+
+type PublicListAdminTokensResponse struct {
+	Data  []models.AdminToken `json:"data"`
+	Total int                 `json:"total"`
+}
+
+func (s *Server) PublicListAdminTokens(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	adminTokens, err := s.adminTokenManager.List(ctx)
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to list admin tokens"})
+	}
+
+	return c.JSON(PublicListAdminTokensResponse{Data: adminTokens, Total: len(adminTokens)})
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicRevokeAdminTokenResponse struct {
+	Success bool `json:"success"`
+}
+
+func (s *Server) PublicRevokeAdminToken(c fiber.Ctx) error {
+	ctx := c.Context()
+	adminTokenID, err := uuid.Parse(c.Params("id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid admin token id"})
+	}
+
+	err = s.adminTokenManager.Revoke(ctx, adminTokenID)
+
+	if errors.Is(err, manager.ErrAdminTokenNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "admin token not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to revoke admin token"})
+	}
+
+	return c.JSON(PublicRevokeAdminTokenResponse{Success: true})
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicCreateClusterAPIKeyResponse struct {
+	Data *manager.ClusterAPIKeyWithSecret `json:"data"`
+}
+
+func (s *Server) PublicCreateClusterAPIKey(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	var req manager.CreateClusterAPIKeyRequest
+
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+	}
+
+	if req.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "name is required"})
+	}
+
+	apiKey, err := s.clusterManager.CreateAPIKey(ctx, c.Params("id"), req)
+
+	if errors.Is(err, manager.ErrClusterNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "cluster not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to create api key"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(PublicCreateClusterAPIKeyResponse{Data: apiKey})
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/models"
+)
+
+type PublicListClusterAPIKeysResponse struct {
+	Data  []models.ClusterAPIKey `json:"data"`
+	Total int                    `json:"total"`
+}
+
+func (s *Server) PublicListClusterAPIKeys(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	apiKeys, err := s.clusterManager.ListAPIKeys(ctx, c.Params("id"), c.Query("name"))
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to list api keys"})
+	}
+
+	return c.JSON(PublicListClusterAPIKeysResponse{Data: apiKeys, Total: len(apiKeys)})
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicRevokeClusterAPIKeyResponse struct {
+	Success bool `json:"success"`
+}
+
+func (s *Server) PublicRevokeClusterAPIKey(c fiber.Ctx) error {
+	ctx := c.Context()
+	keyID, err := uuid.Parse(c.Params("key_id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid api key id"})
+	}
+
+	err = s.clusterManager.RevokeAPIKey(ctx, c.Params("id"), keyID)
+
+	if errors.Is(err, manager.ErrClusterAPIKeyNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "api key not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to revoke api key"})
+	}
+
+	// Drop cached verifications so the revocation takes effect immediately
+	s.apiKeyCache.InvalidateKey(keyID)
+
+	return c.JSON(PublicRevokeClusterAPIKeyResponse{Success: true})
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicRotateClusterAPIKeyResponse struct {
+	Data *manager.ClusterAPIKeyWithSecret `json:"data"`
+}
+
+func (s *Server) PublicRotateClusterAPIKey(c fiber.Ctx) error {
+	ctx := c.Context()
+	keyID, err := uuid.Parse(c.Params("key_id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid api key id"})
+	}
+
+	var req manager.RotateClusterAPIKeyRequest
+
+	if len(c.Body()) > 0 {
+		if err := c.Bind().JSON(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+		}
+	}
+
+	apiKey, err := s.clusterManager.RotateAPIKey(ctx, c.Params("id"), keyID, req)
+
+	if errors.Is(err, manager.ErrClusterAPIKeyNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "api key not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to rotate api key"})
+	}
+
+	// Drop cached verifications so the old key's shortened expiry takes effect immediately
+	s.apiKeyCache.InvalidateKey(keyID)
+
+	return c.Status(fiber.StatusCreated).JSON(PublicRotateClusterAPIKeyResponse{Data: apiKey})
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/manager"
+	"github.com/targc/kontrol/pkg/models"
+)
+
+type PublicCreateClusterResponse struct {
+	Data *models.Cluster `json:"data"`
+}
+
+func (s *Server) PublicCreateCluster(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	var req manager.CreateClusterRequest
+
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+	}
+
+	if req.ID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "id is required"})
+	}
+
+	cluster, err := s.clusterManager.Create(ctx, req)
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to create cluster"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(PublicCreateClusterResponse{Data: cluster})
+}
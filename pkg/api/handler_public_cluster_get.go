@@ -0,0 +1,27 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/manager"
+	"github.com/targc/kontrol/pkg/models"
+)
+
+type PublicGetClusterResponse struct {
+	Data *models.Cluster `json:"data"`
+}
+
+func (s *Server) PublicGetCluster(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	cluster, err := s.clusterManager.Get(ctx, c.Params("id"))
+
+	if errors.Is(err, manager.ErrClusterNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "cluster not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to get cluster"})
+	}
+
+	return c.JSON(PublicGetClusterResponse{Data: cluster})
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/manager"
+	"github.com/targc/kontrol/pkg/models"
+)
+
+type PublicSetClusterLabelsResponse struct {
+	Data *models.Cluster `json:"data"`
+}
+
+func (s *Server) PublicSetClusterLabels(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	var req manager.SetClusterLabelsRequest
+
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+	}
+
+	cluster, err := s.clusterManager.SetLabels(ctx, c.Params("id"), req.Labels)
+
+	if errors.Is(err, manager.ErrClusterNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "cluster not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to update cluster labels"})
+	}
+
+	return c.JSON(PublicSetClusterLabelsResponse{Data: cluster})
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/models"
+)
+
+type PublicListClustersResponse struct {
+	Data  []models.Cluster `json:"data"`
+	Total int              `json:"total"`
+}
+
+func (s *Server) PublicListClusters(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	clusters, err := s.clusterManager.List(ctx)
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to list clusters"})
+	}
+
+	return c.JSON(PublicListClustersResponse{Data: clusters, Total: len(clusters)})
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicSetGlobalResourceClusterSelectorResponse struct {
+	Data *manager.GlobalResourceWithSyncStatus `json:"data"`
+}
+
+func (s *Server) PublicSetGlobalResourceClusterSelector(c fiber.Ctx) error {
+	ctx := c.Context()
+	globalResourceID, err := uuid.Parse(c.Params("id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid global resource id"})
+	}
+
+	var req manager.SetClusterSelectorRequest
+
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+	}
+
+	globalResource, err := s.globalResourceManager.SetClusterSelector(ctx, globalResourceID, req.ClusterSelector)
+
+	if errors.Is(err, manager.ErrGlobalResourceNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "global resource not found"})
+	} else if errors.Is(err, manager.ErrInvalidResource) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to update global resource"})
+	}
+
+	return c.JSON(PublicSetGlobalResourceClusterSelectorResponse{Data: globalResource})
+}
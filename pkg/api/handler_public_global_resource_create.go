@@ -0,0 +1,53 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicCreateGlobalResourceResponse struct {
+	Data *manager.GlobalResourceWithSyncStatus `json:"data"`
+}
+
+func (s *Server) PublicCreateGlobalResource(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	var req manager.CreateGlobalResourceRequest
+
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+	}
+
+	if msg := validateCreateGlobalResourceRequest(&req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: msg})
+	}
+
+	globalResource, err := s.globalResourceManager.Create(ctx, req)
+
+	if errors.Is(err, manager.ErrInvalidResource) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to create global resource"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(PublicCreateGlobalResourceResponse{Data: globalResource})
+}
+
+// validateCreateGlobalResourceRequest returns an error message if the request is missing required fields
+func validateCreateGlobalResourceRequest(req *manager.CreateGlobalResourceRequest) string {
+	if req.Kind == "" {
+		return "kind is required"
+	}
+
+	if req.Name == "" {
+		return "name is required"
+	}
+
+	if len(req.DesiredSpec) == 0 {
+		return "desired_spec is required"
+	}
+
+	return ""
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicDeleteGlobalResourceResponse struct {
+	ID      uuid.UUID `json:"id"`
+	Status  string    `json:"status"`
+	Message string    `json:"message"`
+}
+
+func (s *Server) PublicDeleteGlobalResource(c fiber.Ctx) error {
+	ctx := c.Context()
+	globalResourceID, err := uuid.Parse(c.Params("id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid global resource id"})
+	}
+
+	err = s.globalResourceManager.Delete(ctx, globalResourceID)
+
+	if errors.Is(err, manager.ErrGlobalResourceNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "global resource not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to delete global resource"})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(PublicDeleteGlobalResourceResponse{
+		ID:      globalResourceID,
+		Status:  "deleting",
+		Message: "Global resource marked for deletion",
+	})
+}
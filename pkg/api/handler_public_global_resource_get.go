@@ -0,0 +1,32 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicGetGlobalResourceResponse struct {
+	Data *manager.GlobalResourceWithSyncStatus `json:"data"`
+}
+
+func (s *Server) PublicGetGlobalResource(c fiber.Ctx) error {
+	ctx := c.Context()
+	globalResourceID, err := uuid.Parse(c.Params("id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid global resource id"})
+	}
+
+	globalResource, err := s.globalResourceManager.Get(ctx, globalResourceID)
+
+	if errors.Is(err, manager.ErrGlobalResourceNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "global resource not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to get global resource"})
+	}
+
+	return c.JSON(PublicGetGlobalResourceResponse{Data: globalResource})
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicListGlobalResourcesResponse struct {
+	Data  []*manager.GlobalResourceWithSyncStatus `json:"data"`
+	Total int                                     `json:"total"`
+}
+
+func (s *Server) PublicListGlobalResources(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	globalResources, err := s.globalResourceManager.List(ctx)
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to list global resources"})
+	}
+
+	return c.JSON(PublicListGlobalResourcesResponse{Data: globalResources, Total: len(globalResources)})
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/manager"
+	"github.com/targc/kontrol/pkg/models"
+)
+
+type PublicListGlobalResourceRevisionsResponse struct {
+	Data  []models.GlobalResourceRevision `json:"data"`
+	Total int                             `json:"total"`
+}
+
+func (s *Server) PublicListGlobalResourceRevisions(c fiber.Ctx) error {
+	ctx := c.Context()
+	globalResourceID, err := uuid.Parse(c.Params("id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid global resource id"})
+	}
+
+	revisions, err := s.globalResourceManager.ListRevisions(ctx, globalResourceID)
+
+	if errors.Is(err, manager.ErrGlobalResourceNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "global resource not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to list global resource revisions"})
+	}
+
+	return c.JSON(PublicListGlobalResourceRevisionsResponse{Data: revisions, Total: len(revisions)})
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicRollbackGlobalResourceResponse struct {
+	Data *manager.GlobalResourceWithSyncStatus `json:"data"`
+}
+
+func (s *Server) PublicRollbackGlobalResource(c fiber.Ctx) error {
+	ctx := c.Context()
+	globalResourceID, err := uuid.Parse(c.Params("id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid global resource id"})
+	}
+
+	var req manager.RollbackRequest
+
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+	}
+
+	if req.Revision <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "revision is required"})
+	}
+
+	globalResource, err := s.globalResourceManager.Rollback(ctx, globalResourceID, req.Revision)
+
+	if errors.Is(err, manager.ErrGlobalResourceNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "global resource not found"})
+	} else if errors.Is(err, manager.ErrRevisionNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "revision not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to roll back global resource"})
+	}
+
+	return c.JSON(PublicRollbackGlobalResourceResponse{Data: globalResource})
+}
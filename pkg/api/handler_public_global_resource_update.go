@@ -0,0 +1,42 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicUpdateGlobalResourceResponse struct {
+	Data *manager.GlobalResourceWithSyncStatus `json:"data"`
+}
+
+func (s *Server) PublicUpdateGlobalResource(c fiber.Ctx) error {
+	ctx := c.Context()
+	globalResourceID, err := uuid.Parse(c.Params("id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid global resource id"})
+	}
+
+	var req manager.UpdateGlobalResourceRequest
+
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+	}
+
+	if len(req.DesiredSpec) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "desired_spec is required"})
+	}
+
+	globalResource, err := s.globalResourceManager.Update(ctx, globalResourceID, req.DesiredSpec, req.Revision)
+
+	if errors.Is(err, manager.ErrGlobalResourceNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "global resource not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to update global resource"})
+	}
+
+	return c.JSON(PublicUpdateGlobalResourceResponse{Data: globalResource})
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicUpsertGlobalResourceResponse struct {
+	Data *manager.GlobalResourceWithSyncStatus `json:"data"`
+}
+
+func (s *Server) PublicUpsertGlobalResource(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	var req manager.CreateGlobalResourceRequest
+
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+	}
+
+	if msg := validateCreateGlobalResourceRequest(&req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: msg})
+	}
+
+	globalResource, err := s.globalResourceManager.Upsert(ctx, req)
+
+	if errors.Is(err, manager.ErrInvalidResource) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to upsert global resource"})
+	}
+
+	return c.JSON(PublicUpsertGlobalResourceResponse{Data: globalResource})
+}
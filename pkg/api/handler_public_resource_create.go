@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicCreateResourceResponse struct {
+	Data *manager.ResourceWithState `json:"data"`
+}
+
+func (s *Server) PublicCreateResource(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	var req manager.CreateResourceRequest
+
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+	}
+
+	if msg := validateCreateResourceRequest(&req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: msg})
+	}
+
+	resource, err := s.resourceManager.Create(ctx, req)
+
+	if errors.Is(err, manager.ErrInvalidResource) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to create resource"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(PublicCreateResourceResponse{Data: resource})
+}
+
+// validateCreateResourceRequest returns an error message if the request is missing required fields
+func validateCreateResourceRequest(req *manager.CreateResourceRequest) string {
+	if req.ClusterID == "" {
+		return "cluster_id is required"
+	}
+
+	if req.Kind == "" {
+		return "kind is required"
+	}
+
+	if req.Name == "" {
+		return "name is required"
+	}
+
+	if len(req.DesiredSpec) == 0 {
+		return "desired_spec is required"
+	}
+
+	return ""
+}
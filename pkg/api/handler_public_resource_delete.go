@@ -0,0 +1,38 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicDeleteResourceResponse struct {
+	ID      uuid.UUID `json:"id"`
+	Status  string    `json:"status"`
+	Message string    `json:"message"`
+}
+
+func (s *Server) PublicDeleteResource(c fiber.Ctx) error {
+	ctx := c.Context()
+	resourceID, err := uuid.Parse(c.Params("id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid resource id"})
+	}
+
+	err = s.resourceManager.Delete(ctx, resourceID)
+
+	if errors.Is(err, manager.ErrResourceNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "resource not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to delete resource"})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(PublicDeleteResourceResponse{
+		ID:      resourceID,
+		Status:  "deleting",
+		Message: "Resource marked for deletion",
+	})
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicGetResourceResponse struct {
+	Data *manager.ResourceWithState `json:"data"`
+}
+
+func (s *Server) PublicGetResource(c fiber.Ctx) error {
+	ctx := c.Context()
+	resourceID, err := uuid.Parse(c.Params("id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid resource id"})
+	}
+
+	resource, err := s.resourceManager.Get(ctx, resourceID)
+
+	if errors.Is(err, manager.ErrResourceNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "resource not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to get resource"})
+	}
+
+	return c.JSON(PublicGetResourceResponse{Data: resource})
+}
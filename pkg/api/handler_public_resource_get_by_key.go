@@ -0,0 +1,35 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicGetResourceByKeyResponse struct {
+	Data *manager.ResourceWithState `json:"data"`
+}
+
+func (s *Server) PublicGetResourceByKey(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	clusterID := c.Query("cluster_id")
+	namespace := c.Query("namespace")
+	kind := c.Query("kind")
+	name := c.Query("name")
+
+	if clusterID == "" || kind == "" || name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "cluster_id, kind and name are required"})
+	}
+
+	resource, err := s.resourceManager.GetByKey(ctx, clusterID, namespace, kind, name)
+
+	if errors.Is(err, manager.ErrResourceNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "resource not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to get resource"})
+	}
+
+	return c.JSON(PublicGetResourceByKeyResponse{Data: resource})
+}
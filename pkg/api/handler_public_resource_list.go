@@ -0,0 +1,23 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicListResourcesResponse struct {
+	Data  []*manager.ResourceWithState `json:"data"`
+	Total int                          `json:"total"`
+}
+
+func (s *Server) PublicListResources(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	resources, err := s.resourceManager.List(ctx, c.Query("cluster_id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to list resources"})
+	}
+
+	return c.JSON(PublicListResourcesResponse{Data: resources, Total: len(resources)})
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicListDriftedResourcesResponse struct {
+	Data  []*manager.ResourceWithState `json:"data"`
+	Total int                          `json:"total"`
+}
+
+func (s *Server) PublicListDriftedResources(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	resources, err := s.resourceManager.ListDrifted(ctx, c.Query("cluster_id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to list drifted resources"})
+	}
+
+	return c.JSON(PublicListDriftedResourcesResponse{Data: resources, Total: len(resources)})
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/manager"
+	"github.com/targc/kontrol/pkg/models"
+)
+
+type PublicListResourceRevisionsResponse struct {
+	Data  []models.ResourceRevision `json:"data"`
+	Total int                       `json:"total"`
+}
+
+func (s *Server) PublicListResourceRevisions(c fiber.Ctx) error {
+	ctx := c.Context()
+	resourceID, err := uuid.Parse(c.Params("id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid resource id"})
+	}
+
+	revisions, err := s.resourceManager.ListRevisions(ctx, resourceID)
+
+	if errors.Is(err, manager.ErrResourceNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "resource not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to list resource revisions"})
+	}
+
+	return c.JSON(PublicListResourceRevisionsResponse{Data: revisions, Total: len(revisions)})
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicRollbackResourceResponse struct {
+	Data *manager.ResourceWithState `json:"data"`
+}
+
+func (s *Server) PublicRollbackResource(c fiber.Ctx) error {
+	ctx := c.Context()
+	resourceID, err := uuid.Parse(c.Params("id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid resource id"})
+	}
+
+	var req manager.RollbackRequest
+
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+	}
+
+	if req.Revision <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "revision is required"})
+	}
+
+	resource, err := s.resourceManager.Rollback(ctx, resourceID, req.Revision)
+
+	if errors.Is(err, manager.ErrResourceNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "resource not found"})
+	} else if errors.Is(err, manager.ErrRevisionNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "revision not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to roll back resource"})
+	}
+
+	return c.JSON(PublicRollbackResourceResponse{Data: resource})
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicSetResourceSelfHealResponse struct {
+	Data *manager.ResourceWithState `json:"data"`
+}
+
+func (s *Server) PublicSetResourceSelfHeal(c fiber.Ctx) error {
+	ctx := c.Context()
+	resourceID, err := uuid.Parse(c.Params("id"))
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid resource id"})
+	}
+
+	var req manager.SetSelfHealRequest
+
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+	}
+
+	resource, err := s.resourceManager.SetSelfHeal(ctx, resourceID, req.Enabled)
+
+	if errors.Is(err, manager.ErrResourceNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "resource not found"})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to update resource"})
+	}
+
+	return c.JSON(PublicSetResourceSelfHealResponse{Data: resource})
+}
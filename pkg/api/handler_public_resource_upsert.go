@@ -0,0 +1,36 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/manager"
+)
+
+type PublicUpsertResourceResponse struct {
+	Data *manager.ResourceWithState `json:"data"`
+}
+
+func (s *Server) PublicUpsertResource(c fiber.Ctx) error {
+	ctx := c.Context()
+
+	var req manager.CreateResourceRequest
+
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+	}
+
+	if msg := validateCreateResourceRequest(&req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: msg})
+	}
+
+	resource, err := s.resourceManager.Upsert(ctx, req)
+
+	if errors.Is(err, manager.ErrInvalidResource) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to upsert resource"})
+	}
+
+	return c.JSON(PublicUpsertResourceResponse{Data: resource})
+}
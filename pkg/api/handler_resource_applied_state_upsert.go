@@ -2,9 +2,11 @@ package api
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/database"
 	"github.com/targc/kontrol/pkg/models"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -16,6 +18,8 @@ type UpsertAppliedStateRequest struct {
 	Revision     int             `json:"revision"`
 	Status       string          `json:"status"`
 	ErrorMessage *string         `json:"error_message"`
+
+	AttemptedGeneration int `json:"attempted_generation"`
 }
 
 type UpsertAppliedStateResponse struct {
@@ -60,21 +64,73 @@ func (s *Server) UpsertAppliedState(c fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to query applied state"})
 	}
 
+	now := time.Now()
+
+	updates := map[string]interface{}{
+		"status":          req.Status,
+		"error_message":   req.ErrorMessage,
+		"last_attempt_at": now,
+		"attempt_count":   appliedState.AttemptCount + 1,
+	}
+
+	if req.Status == "error" {
+		// A failed apply leaves spec, generation and revision at the last successful apply.
+		// Consecutive failures of the same generation back off exponentially; a new generation starts over.
+		retryCount := 1
+
+		if appliedState.LastAttemptedGeneration == req.AttemptedGeneration {
+			retryCount = appliedState.RetryCount + 1
+		}
+
+		updates["last_attempted_generation"] = req.AttemptedGeneration
+		updates["retry_count"] = retryCount
+
+		if s.retryPolicy.exhausted(retryCount) {
+			updates["next_attempt_at"] = nil
+		} else {
+			updates["next_attempt_at"] = now.Add(s.retryPolicy.backoff(retryCount))
+		}
+	} else {
+		updates["spec"] = []byte(req.Spec)
+		updates["generation"] = req.Generation
+		updates["revision"] = req.Revision
+		updates["last_attempted_generation"] = req.Generation
+		updates["retry_count"] = 0
+		updates["next_attempt_at"] = nil
+		updates["missing_since"] = nil
+
+		if appliedState.HealRequestedAt != nil {
+			updates["heal_requested_at"] = nil
+			updates["last_healed_at"] = now
+		}
+	}
+
 	err = tx.
 		Model(&appliedState).
-		Updates(map[string]interface{}{
-			"spec":          []byte(req.Spec),
-			"generation":    req.Generation,
-			"revision":      req.Revision,
-			"status":        req.Status,
-			"error_message": req.ErrorMessage,
-		}).
+		Updates(updates).
 		Error
 
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to update applied state"})
 	}
 
+	// An apply of a derived resource may let a staged rollout move on to more clusters
+	err = tx.
+		Exec(`
+			SELECT pg_notify(?, json_build_object('table', 'k_global_resources', 'id', gr.id, 'cluster_id', '')::text)
+			FROM k_resources r
+			JOIN k_global_resources gr
+				ON gr.id = r.global_resource_id
+				AND gr.deleted_at IS NULL
+			WHERE r.id = ?
+			AND jsonb_typeof(gr.rollout_strategy) = 'object'
+		`, database.ChangesChannel, resourceID).
+		Error
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to notify rollout"})
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to commit"})
 	}
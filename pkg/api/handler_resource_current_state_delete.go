@@ -18,8 +18,10 @@ func (s *Server) DeleteCurrentState(c fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid resource id"})
 	}
 
-	err = s.db.
-		WithContext(ctx).
+	tx := s.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	err = tx.
 		Unscoped().
 		Where("resource_id = ?", resourceID).
 		Delete(&models.ResourceCurrentState{}).
@@ -29,5 +31,18 @@ func (s *Server) DeleteCurrentState(c fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to delete current state"})
 	}
 
+	// The object is gone from K8s while the resource still exists: deleted out-of-band
+	if err := markMissing(tx, resourceID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to mark resource missing"})
+	}
+
+	if err := requestHeal(tx, resourceID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to request self-heal"})
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to commit"})
+	}
+
 	return c.JSON(DeleteCurrentStateResponse{Success: true})
 }
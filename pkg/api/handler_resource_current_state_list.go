@@ -0,0 +1,62 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/models"
+)
+
+type ListCurrentStateResourcesResponse struct {
+	Data []models.Resource `json:"data"`
+}
+
+// ListCurrentStateResources lists the cluster's resources that have a current state,
+// ordered by id. Pass the last id of a page as `after` to fetch the next page.
+func (s *Server) ListCurrentStateResources(c fiber.Ctx) error {
+	clusterID := c.Locals("cluster_id").(string)
+	ctx := c.Context()
+
+	limit := 100
+	if l, err := strconv.Atoi(c.Query("limit", "100")); err == nil && l > 0 {
+		limit = l
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	after := uuid.Nil
+
+	if a := c.Query("after"); a != "" {
+		parsed, err := uuid.Parse(a)
+
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid after cursor"})
+		}
+
+		after = parsed
+	}
+
+	var resources []models.Resource
+
+	err := s.db.
+		WithContext(ctx).
+		Raw(`
+			SELECT r.* FROM k_resources r
+			JOIN k_resource_current_states cs ON r.id = cs.resource_id AND cs.deleted_at IS NULL
+			WHERE r.cluster_id = ?
+			AND r.deleted_at IS NULL
+			AND r.id > ?
+			ORDER BY r.id ASC
+			LIMIT ?
+		`, clusterID, after, limit).
+		Scan(&resources).
+		Error
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to list resources"})
+	}
+
+	return c.JSON(ListCurrentStateResourcesResponse{Data: resources})
+}
@@ -2,9 +2,12 @@ package api
 
 import (
 	"encoding/json"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/k8s"
 	"github.com/targc/kontrol/pkg/models"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -12,9 +15,14 @@ import (
 
 type UpsertCurrentStateRequest struct {
 	Spec               json.RawMessage `json:"spec"`
+	Object             json.RawMessage `json:"object"`
+	Status             json.RawMessage `json:"status"`
+	Conditions         json.RawMessage `json:"conditions"`
 	Generation         int             `json:"generation"`
 	Revision           int             `json:"revision"`
 	K8sResourceVersion string          `json:"k8s_resource_version"`
+	Health             string          `json:"health"`
+	HealthMessage      string          `json:"health_message"`
 }
 
 type UpsertCurrentStateResponse struct {
@@ -67,14 +75,50 @@ func (s *Server) UpsertCurrentState(c fiber.Ctx) error {
 		return c.JSON(UpsertCurrentStateResponse{Success: true})
 	}
 
+	updates := map[string]interface{}{
+		"spec":                 []byte(req.Spec),
+		"object":               []byte(req.Object),
+		"status":               []byte(req.Status),
+		"conditions":           []byte(req.Conditions),
+		"generation":           req.Generation,
+		"revision":             req.Revision,
+		"k8s_resource_version": req.K8sResourceVersion,
+		"health":               req.Health,
+		"health_message":       req.HealthMessage,
+		"drifted":              false,
+		"drift_summary":        "",
+		"drift_checked_at":     time.Now(),
+	}
+
+	if err := clearMissing(tx, resourceID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to clear missing flag"})
+	}
+
+	var resource models.Resource
+
+	err = tx.
+		Select("id", "kind", "desired_spec", "generation").
+		First(&resource, resourceID).
+		Error
+
+	// Drift is only meaningful once the live object carries the latest generation;
+	// before that the difference is a pending apply
+	if err == nil && resource.Generation == req.Generation {
+		diffs, err := k8s.DetectDrift(resource.Kind, resource.DesiredSpec, req.Object)
+
+		if err == nil && len(diffs) > 0 {
+			updates["drifted"] = true
+			updates["drift_summary"] = strings.Join(diffs, "\n")
+
+			if err := requestHeal(tx, resourceID); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to request self-heal"})
+			}
+		}
+	}
+
 	err = tx.
 		Model(&currentState).
-		Updates(map[string]interface{}{
-			"spec":                 []byte(req.Spec),
-			"generation":           req.Generation,
-			"revision":             req.Revision,
-			"k8s_resource_version": req.K8sResourceVersion,
-		}).
+		Updates(updates).
 		Error
 
 	if err != nil {
@@ -2,6 +2,7 @@ package api
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/targc/kontrol/pkg/models"
@@ -25,7 +26,10 @@ func (s *Server) ListOutOfSyncResources(c fiber.Ctx) error {
 
 	var resources []models.Resource
 
-	// Resources where generation != applied_state.generation OR applied_state doesn't exist
+	// Resources where generation != applied_state.generation OR applied_state doesn't exist,
+	// plus self-healing resources with a pending heal whose last heal is older than selfHealInterval,
+	// skipping generations whose apply failed and are still cooling down or out of retry budget.
+	// First attempts are handed out before retries so failing resources cannot starve healthy ones.
 	err := s.db.
 		WithContext(ctx).
 		Raw(`
@@ -33,10 +37,20 @@ func (s *Server) ListOutOfSyncResources(c fiber.Ctx) error {
 			LEFT JOIN k_resource_applied_states a ON r.id = a.resource_id AND a.deleted_at IS NULL
 			WHERE r.cluster_id = ?
 			AND r.deleted_at IS NULL
-			AND (a.id IS NULL OR a.generation != r.generation)
-			ORDER BY r.created_at ASC
+			AND (
+				a.id IS NULL
+				OR a.generation != r.generation
+				OR (r.self_heal AND a.heal_requested_at IS NOT NULL AND (a.last_healed_at IS NULL OR a.last_healed_at <= ?))
+			)
+			AND (
+				a.id IS NULL
+				OR a.retry_count = 0
+				OR a.last_attempted_generation != r.generation
+				OR ((a.next_attempt_at IS NULL OR a.next_attempt_at <= NOW()) AND (? = 0 OR a.retry_count < ?))
+			)
+			ORDER BY CASE WHEN a.last_attempted_generation = r.generation THEN a.retry_count ELSE 0 END ASC, r.created_at ASC
 			LIMIT ?
-		`, clusterID, limit).
+		`, clusterID, time.Now().Add(-s.selfHealInterval), s.retryPolicy.Budget, s.retryPolicy.Budget, limit).
 		Scan(&resources).
 		Error
 
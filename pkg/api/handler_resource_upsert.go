@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/manager"
+	"github.com/targc/kontrol/pkg/models"
+)
+
+type UpsertResourceRequest struct {
+	GlobalResourceID uuid.UUID       `json:"global_resource_id"`
+	Namespace        string          `json:"namespace"`
+	Kind             string          `json:"kind"`
+	Name             string          `json:"name"`
+	APIVersion       string          `json:"api_version"`
+	DesiredSpec      json.RawMessage `json:"desired_spec"`
+	Revision         int             `json:"revision"`
+	SelfHeal         bool            `json:"self_heal"`
+}
+
+type UpsertResourceResponse struct {
+	Data *models.Resource `json:"data"`
+}
+
+// UpsertResource creates or updates, by key, the resource derived from a global resource
+// in the calling cluster
+func (s *Server) UpsertResource(c fiber.Ctx) error {
+	clusterID := c.Locals("cluster_id").(string)
+	ctx := c.Context()
+
+	var req UpsertResourceRequest
+
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "invalid request body"})
+	}
+
+	if req.GlobalResourceID == uuid.Nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "global_resource_id is required"})
+	}
+
+	resource, err := s.resourceManager.UpsertDerived(ctx, req.GlobalResourceID, manager.CreateResourceRequest{
+		ClusterID:   clusterID,
+		Namespace:   req.Namespace,
+		Kind:        req.Kind,
+		Name:        req.Name,
+		APIVersion:  req.APIVersion,
+		DesiredSpec: req.DesiredSpec,
+		SelfHeal:    &req.SelfHeal,
+	}, req.Revision)
+
+	if errors.Is(err, manager.ErrInvalidResource) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+	} else if errors.Is(err, manager.ErrResourceConflict) {
+		if err := s.recordSyncConflict(ctx, req.GlobalResourceID, clusterID, err.Error()); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to record conflict"})
+		}
+
+		return c.Status(fiber.StatusConflict).JSON(ErrorResponse{Error: err.Error()})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "failed to upsert resource"})
+	}
+
+	return c.JSON(UpsertResourceResponse{Data: &resource.Resource})
+}
+
+// recordSyncConflict records on the synced state that the resource derived from a global
+// resource could not be upserted, so the global resource is held back for
+// syncConflictRetryInterval instead of being handed out on every poll
+func (s *Server) recordSyncConflict(ctx context.Context, globalResourceID uuid.UUID, clusterID, conflict string) error {
+	return s.db.
+		WithContext(ctx).
+		Exec(`
+			INSERT INTO k_global_resource_synced_states (id, global_resource_id, cluster_id, synced_generation, conflict, conflict_at, created_at, updated_at)
+			VALUES (?, ?, ?, 0, ?, NOW(), NOW(), NOW())
+			ON CONFLICT (global_resource_id, cluster_id)
+			DO UPDATE SET
+				conflict = EXCLUDED.conflict,
+				conflict_at = EXCLUDED.conflict_at,
+				updated_at = NOW()
+		`, uuid.Must(uuid.NewV7()), globalResourceID, clusterID, conflict).
+		Error
+}
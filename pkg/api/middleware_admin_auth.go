@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/targc/kontrol/pkg/manager"
+	"github.com/targc/kontrol/pkg/models"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// adminScopeRank orders admin scopes so that a higher scope grants every lower one
+var adminScopeRank = map[string]int{
+	models.AdminScopeReadOnly: 1,
+	models.AdminScopeWrite:    2,
+	models.AdminScopeAdmin:    3,
+}
+
+// adminTokenLastUsedInterval throttles last_used_at writes to one per token per interval
+const adminTokenLastUsedInterval = time.Minute
+
+func (s *Server) AdminAuthMiddleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		token, ok := strings.CutPrefix(c.Get("Authorization"), "Bearer ")
+
+		if !ok || token == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "missing admin token"})
+		}
+
+		adminToken, err := s.verifyAdminToken(c.Context(), token)
+
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "internal error"})
+		}
+
+		if adminToken == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "invalid admin token"})
+		}
+
+		now := time.Now()
+
+		if adminToken.LastUsedAt == nil || now.Sub(*adminToken.LastUsedAt) >= adminTokenLastUsedInterval {
+			s.db.
+				WithContext(c.Context()).
+				Model(adminToken).
+				UpdateColumn("last_used_at", now)
+		}
+
+		c.Locals("admin_token_id", adminToken.ID)
+		c.Locals("admin_scope", adminToken.Scope)
+
+		// Attribute changes made with this token in the revision history
+		c.SetContext(manager.WithAuthor(c.Context(), "admin-token:"+adminToken.Name))
+
+		return c.Next()
+	}
+}
+
+// verifyAdminToken returns the active admin token matching the presented value, or nil.
+// Tokens minted by the API are found by their lookup ID and need a single bcrypt
+// comparison; only tokens without one (the bootstrap token) are compared one by one.
+// Verified tokens are cached, but the row is still loaded so revocation and expiry
+// take effect immediately.
+func (s *Server) verifyAdminToken(ctx context.Context, token string) (*models.AdminToken, error) {
+	now := time.Now()
+
+	if id, ok := s.adminTokenCache.Get("", token); ok {
+		var adminToken models.AdminToken
+
+		err := s.db.
+			WithContext(ctx).
+			Where("expires_at IS NULL OR expires_at > ?", now).
+			First(&adminToken, id).
+			Error
+
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		return &adminToken, nil
+	}
+
+	query := s.db.
+		WithContext(ctx).
+		Where("expires_at IS NULL OR expires_at > ?", now)
+
+	if lookupID, ok := manager.AdminTokenLookupID(token); ok {
+		query = query.Where("lookup_id = ?", lookupID)
+	} else {
+		query = query.Where("lookup_id IS NULL")
+	}
+
+	var adminTokens []models.AdminToken
+
+	err := query.
+		Find(&adminTokens).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, adminToken := range adminTokens {
+		if bcrypt.CompareHashAndPassword([]byte(adminToken.TokenHash), []byte(token)) == nil {
+			s.adminTokenCache.Add("", token, adminToken.ID, adminToken.ExpiresAt)
+			return &adminToken, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// RequireScope rejects requests whose admin token scope is lower than scope.
+// It must run after AdminAuthMiddleware.
+func (s *Server) RequireScope(scope string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		granted, _ := c.Locals("admin_scope").(string)
+
+		if adminScopeRank[granted] < adminScopeRank[scope] {
+			return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: "insufficient scope"})
+		}
+
+		return c.Next()
+	}
+}
@@ -1,9 +1,12 @@
 package api
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v3"
 	"github.com/targc/kontrol/pkg/models"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 func (s *Server) AuthMiddleware() fiber.Handler {
@@ -19,11 +22,34 @@ func (s *Server) AuthMiddleware() fiber.Handler {
 			return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "missing cluster id"})
 		}
 
+		// A cached verification skips bcrypt, but the key row is still loaded by ID so
+		// that a revocation or rotation made through another API replica takes effect
+		// immediately
+		if keyID, ok := s.apiKeyCache.Get(clusterID, apiKey); ok {
+			var key models.ClusterAPIKey
+
+			err := s.db.
+				WithContext(c.Context()).
+				Where("cluster_id = ? AND (expires_at IS NULL OR expires_at > ?)", clusterID, time.Now()).
+				First(&key, keyID).
+				Error
+
+			if err == gorm.ErrRecordNotFound {
+				s.apiKeyCache.InvalidateKey(keyID)
+				return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "invalid api key"})
+			} else if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "internal error"})
+			}
+
+			c.Locals("cluster_id", clusterID)
+			return c.Next()
+		}
+
 		var keys []models.ClusterAPIKey
 
 		err := s.db.
 			WithContext(c.Context()).
-			Where("cluster_id = ? AND deleted_at IS NULL", clusterID).
+			Where("cluster_id = ? AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", clusterID, time.Now()).
 			Find(&keys).
 			Error
 
@@ -33,6 +59,7 @@ func (s *Server) AuthMiddleware() fiber.Handler {
 
 		for _, key := range keys {
 			if bcrypt.CompareHashAndPassword([]byte(key.KeyHash), []byte(apiKey)) == nil {
+				s.apiKeyCache.Add(clusterID, apiKey, key.ID, key.ExpiresAt)
 				c.Locals("cluster_id", clusterID)
 				return c.Next()
 			}
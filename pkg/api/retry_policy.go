@@ -0,0 +1,32 @@
+package api
+
+import "time"
+
+// RetryPolicy controls how failed applies are backed off. After the n-th
+// consecutive failure of the same generation the resource is not handed out
+// to the reconciler again for BackoffBase * 2^(n-1), capped at BackoffMax.
+type RetryPolicy struct {
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	Budget      int // consecutive failures before a generation is parked until it changes; 0 retries forever
+}
+
+// backoff returns the delay before the next attempt after retryCount consecutive failures
+func (p RetryPolicy) backoff(retryCount int) time.Duration {
+	delay := p.BackoffBase
+
+	for i := 1; i < retryCount && delay < p.BackoffMax; i++ {
+		delay *= 2
+	}
+
+	if p.BackoffMax > 0 && delay > p.BackoffMax {
+		delay = p.BackoffMax
+	}
+
+	return delay
+}
+
+// exhausted reports whether retryCount consecutive failures use up the retry budget
+func (p RetryPolicy) exhausted(retryCount int) bool {
+	return p.Budget > 0 && retryCount >= p.Budget
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{BackoffBase: 10 * time.Second, BackoffMax: 10 * time.Minute}
+
+	tests := []struct {
+		retryCount int
+		want       time.Duration
+	}{
+		{retryCount: 1, want: 10 * time.Second},
+		{retryCount: 2, want: 20 * time.Second},
+		{retryCount: 3, want: 40 * time.Second},
+		{retryCount: 6, want: 320 * time.Second},
+		{retryCount: 7, want: 10 * time.Minute},
+		{retryCount: 1000, want: 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := policy.backoff(tt.retryCount); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.retryCount, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicyExhausted(t *testing.T) {
+	tests := []struct {
+		name       string
+		budget     int
+		retryCount int
+		want       bool
+	}{
+		{name: "unlimited budget", budget: 0, retryCount: 1000, want: false},
+		{name: "below budget", budget: 3, retryCount: 2, want: false},
+		{name: "budget used up", budget: 3, retryCount: 3, want: true},
+		{name: "past budget", budget: 3, retryCount: 4, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := RetryPolicy{BackoffBase: time.Second, BackoffMax: time.Minute, Budget: tt.budget}
+
+			if got := policy.exhausted(tt.retryCount); got != tt.want {
+				t.Errorf("exhausted(%d) = %v, want %v", tt.retryCount, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/models"
+	"gorm.io/gorm"
+)
+
+// requestHeal asks the reconciler to re-apply a self-healing resource whose live object
+// drifted or was deleted out-of-band. Resources without self-heal, deleted resources and
+// resources that were never applied are left alone. ListOutOfSyncResources hands the
+// resource out again once selfHealInterval has passed since its last heal.
+func requestHeal(db *gorm.DB, resourceID uuid.UUID) error {
+	return db.
+		Exec(`
+			UPDATE k_resource_applied_states a
+			SET heal_requested_at = NOW()
+			FROM k_resources r
+			WHERE a.resource_id = r.id
+			AND r.id = ?
+			AND r.self_heal
+			AND r.deleted_at IS NULL
+			AND a.deleted_at IS NULL
+			AND a.heal_requested_at IS NULL
+		`, resourceID).
+		Error
+}
+
+// markMissing records that the applied object of a resource was deleted out-of-band.
+// It is cleared when the watcher sees the object again or the reconciler re-creates it.
+func markMissing(db *gorm.DB, resourceID uuid.UUID) error {
+	return db.
+		Exec(`
+			UPDATE k_resource_applied_states a
+			SET missing_since = NOW()
+			FROM k_resources r
+			WHERE a.resource_id = r.id
+			AND r.id = ?
+			AND r.deleted_at IS NULL
+			AND a.deleted_at IS NULL
+			AND a.missing_since IS NULL
+		`, resourceID).
+		Error
+}
+
+// clearMissing records that the object of a resource exists in K8s again
+func clearMissing(db *gorm.DB, resourceID uuid.UUID) error {
+	return db.
+		Model(&models.ResourceAppliedState{}).
+		Where("resource_id = ? AND missing_since IS NOT NULL", resourceID).
+		Update("missing_since", nil).
+		Error
+}
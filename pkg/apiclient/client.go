@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,10 +15,12 @@ import (
 )
 
 type Client struct {
-	baseURL    string
-	apiKey     string
-	clusterID  string
-	httpClient *http.Client
+	baseURL      string
+	apiKey       string
+	clusterID    string
+	httpClient   *http.Client
+	streamClient *http.Client // no timeout, for the long-lived event stream
+	lastEventAt  atomic.Int64 // unix nanos of the last event or heartbeat, zero while disconnected
 }
 
 func NewClient(baseURL, apiKey, clusterID string) *Client {
@@ -28,6 +31,7 @@ func NewClient(baseURL, apiKey, clusterID string) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		streamClient: &http.Client{},
 	}
 }
 
@@ -86,6 +90,35 @@ func (c *Client) RegisterCluster(ctx context.Context) error {
 	return c.doRequest(ctx, "POST", "/int/api/v1/cluster/register", nil, nil)
 }
 
+// Lease is the leader lease of the cluster
+type Lease struct {
+	Acquired  bool       `json:"acquired"`
+	HolderID  string     `json:"holder_id"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// AcquireLease acquires or renews the leader lease of the cluster for holderID
+func (c *Client) AcquireLease(ctx context.Context, holderID string, duration time.Duration) (*Lease, error) {
+	var resp struct {
+		Data Lease `json:"data"`
+	}
+
+	req := map[string]interface{}{
+		"holder_id":        holderID,
+		"duration_seconds": int(duration.Seconds()),
+	}
+
+	err := c.doRequest(ctx, "POST", "/int/api/v1/cluster/lease", req, &resp)
+
+	return &resp.Data, err
+}
+
+// ReleaseLease gives up the leader lease of the cluster if holderID holds it
+func (c *Client) ReleaseLease(ctx context.Context, holderID string) error {
+	req := map[string]string{"holder_id": holderID}
+	return c.doRequest(ctx, "DELETE", "/int/api/v1/cluster/lease", req, nil)
+}
+
 // ListOutOfSyncResources fetches resources that need reconciliation
 func (c *Client) ListOutOfSyncResources(ctx context.Context, limit int) ([]models.Resource, error) {
 	var resp struct {
@@ -110,13 +143,17 @@ func (c *Client) ListDeletedResources(ctx context.Context, limit int) ([]models.
 	return resp.Data, err
 }
 
-// UpsertAppliedStateRequest is the request body for UpsertAppliedState
+// UpsertAppliedStateRequest is the request body for UpsertAppliedState.
+// Spec, Generation and Revision are ignored for status "error", so the last
+// successful apply is kept.
 type UpsertAppliedStateRequest struct {
 	Spec         json.RawMessage `json:"spec"`
 	Generation   int             `json:"generation"`
 	Revision     int             `json:"revision"`
 	Status       string          `json:"status"`
 	ErrorMessage *string         `json:"error_message"`
+
+	AttemptedGeneration int `json:"attempted_generation"` // generation whose apply failed, set with status "error"
 }
 
 // UpsertAppliedState updates the applied state for a resource
@@ -134,9 +171,14 @@ func (c *Client) HardDeleteResource(ctx context.Context, resourceID uuid.UUID) e
 // UpsertCurrentStateRequest is the request body for UpsertCurrentState
 type UpsertCurrentStateRequest struct {
 	Spec               json.RawMessage `json:"spec"`
+	Object             json.RawMessage `json:"object"`
+	Status             json.RawMessage `json:"status"`
+	Conditions         json.RawMessage `json:"conditions"`
 	Generation         int             `json:"generation"`
 	Revision           int             `json:"revision"`
 	K8sResourceVersion string          `json:"k8s_resource_version"`
+	Health             string          `json:"health"`
+	HealthMessage      string          `json:"health_message"`
 }
 
 // UpsertCurrentState updates the current state for a resource
@@ -151,6 +193,19 @@ func (c *Client) DeleteCurrentState(ctx context.Context, resourceID uuid.UUID) e
 	return c.doRequest(ctx, "DELETE", path, nil, nil)
 }
 
+// ListCurrentStateResources fetches a page of resources that have a current state, ordered by id.
+// Pass the last id of the previous page as after, or uuid.Nil for the first page.
+func (c *Client) ListCurrentStateResources(ctx context.Context, after uuid.UUID, limit int) ([]models.Resource, error) {
+	var resp struct {
+		Data []models.Resource `json:"data"`
+	}
+
+	path := fmt.Sprintf("/int/api/v1/resources/current-states?after=%s&limit=%d", after, limit)
+	err := c.doRequest(ctx, "GET", path, nil, &resp)
+
+	return resp.Data, err
+}
+
 // GlobalResourceForSync represents a global resource that needs syncing
 type GlobalResourceForSync struct {
 	ID          uuid.UUID       `json:"id"`
@@ -161,6 +216,9 @@ type GlobalResourceForSync struct {
 	DesiredSpec json.RawMessage `json:"desired_spec"`
 	Generation  int             `json:"generation"`
 	Revision    int             `json:"revision"`
+	SelfHeal    bool            `json:"self_heal"`
+
+	ClusterLabels map[string]string `json:"cluster_labels"` // labels DesiredSpec was derived from
 }
 
 // ListOutOfSyncGlobalResources fetches global resources that need syncing
@@ -187,10 +245,24 @@ func (c *Client) ListDeletedGlobalResources(ctx context.Context, limit int) ([]m
 	return resp.Data, err
 }
 
-// UpsertSyncedState updates the synced state for a global resource
-func (c *Client) UpsertSyncedState(ctx context.Context, globalResourceID uuid.UUID, syncedGeneration int) error {
+// ListUnmatchedGlobalResources fetches global resources synced to the cluster whose
+// cluster selector no longer matches it
+func (c *Client) ListUnmatchedGlobalResources(ctx context.Context, limit int) ([]models.GlobalResource, error) {
+	var resp struct {
+		Data []models.GlobalResource `json:"data"`
+	}
+
+	path := fmt.Sprintf("/int/api/v1/global-resources/unmatched?limit=%d", limit)
+	err := c.doRequest(ctx, "GET", path, nil, &resp)
+
+	return resp.Data, err
+}
+
+// UpsertSyncedState updates the synced state for a global resource, recording the cluster
+// labels its derived spec was computed from
+func (c *Client) UpsertSyncedState(ctx context.Context, globalResourceID uuid.UUID, syncedGeneration int, clusterLabels map[string]string) error {
 	path := fmt.Sprintf("/int/api/v1/global-resources/%s/synced-state", globalResourceID)
-	req := map[string]int{"synced_generation": syncedGeneration}
+	req := map[string]interface{}{"synced_generation": syncedGeneration, "cluster_labels": clusterLabels}
 
 	return c.doRequest(ctx, "POST", path, req, nil)
 }
@@ -201,18 +273,21 @@ func (c *Client) DeleteSyncedState(ctx context.Context, globalResourceID uuid.UU
 	return c.doRequest(ctx, "DELETE", path, nil, nil)
 }
 
-// CreateResourceRequest is the request body for CreateResource
-type CreateResourceRequest struct {
-	Namespace   string          `json:"namespace"`
-	Kind        string          `json:"kind"`
-	Name        string          `json:"name"`
-	APIVersion  string          `json:"api_version"`
-	DesiredSpec json.RawMessage `json:"desired_spec"`
-	Revision    int             `json:"revision"`
+// UpsertResourceRequest is the request body for UpsertResource
+type UpsertResourceRequest struct {
+	GlobalResourceID uuid.UUID       `json:"global_resource_id"`
+	Namespace        string          `json:"namespace"`
+	Kind             string          `json:"kind"`
+	Name             string          `json:"name"`
+	APIVersion       string          `json:"api_version"`
+	DesiredSpec      json.RawMessage `json:"desired_spec"`
+	Revision         int             `json:"revision"`
+	SelfHeal         bool            `json:"self_heal"`
 }
 
-// CreateResource creates a new resource for the cluster
-func (c *Client) CreateResource(ctx context.Context, req *CreateResourceRequest) (*models.Resource, error) {
+// UpsertResource creates or updates, by key, the resource derived from a global resource
+// for the cluster
+func (c *Client) UpsertResource(ctx context.Context, req *UpsertResourceRequest) (*models.Resource, error) {
 	var resp struct {
 		Data *models.Resource `json:"data"`
 	}
@@ -222,13 +297,8 @@ func (c *Client) CreateResource(ctx context.Context, req *CreateResourceRequest)
 	return resp.Data, err
 }
 
-// SoftDeleteResourceByKey soft-deletes a resource by its key (namespace, kind, name)
-func (c *Client) SoftDeleteResourceByKey(ctx context.Context, namespace, kind, name string) error {
-	req := map[string]string{
-		"namespace": namespace,
-		"kind":      kind,
-		"name":      name,
-	}
-
-	return c.doRequest(ctx, "DELETE", "/int/api/v1/resources/by-key", req, nil)
+// DeleteDerivedResource soft-deletes the resource derived from a global resource in this cluster
+func (c *Client) DeleteDerivedResource(ctx context.Context, globalResourceID uuid.UUID) error {
+	path := fmt.Sprintf("/int/api/v1/global-resources/%s/resource", globalResourceID)
+	return c.doRequest(ctx, "DELETE", path, nil, nil)
 }
@@ -0,0 +1,123 @@
+package apiclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Change event types pushed by the API
+const (
+	ChangeEventResource       = "resource"
+	ChangeEventGlobalResource = "global-resource"
+	ChangeEventResync         = "resync"    // notifications may have been missed, re-check everything
+	ChangeEventHeartbeat      = "heartbeat" // the API is listening for changes; not passed to handlers
+)
+
+// eventStreamIdleTimeout drops the event stream when not even a heartbeat arrived in time
+const eventStreamIdleTimeout = 45 * time.Second
+
+// eventStreamStaleAfter is how long after the last event or heartbeat the stream stops
+// counting as streaming. The API sends heartbeats every 15s, but only while it is
+// listening for changes, so this also covers a stream kept open by keepalive comments.
+const eventStreamStaleAfter = 35 * time.Second
+
+// ChangeEvent tells the worker that a resource of its cluster or a global resource changed
+type ChangeEvent struct {
+	Type string    `json:"type"`
+	ID   uuid.UUID `json:"id"`
+}
+
+// StreamEvents subscribes to the change events of the cluster and calls handle for each
+// event until the stream breaks or ctx is cancelled. onConnect is called once the stream
+// is established, so callers can catch up on changes made while it was down.
+func (c *Client) StreamEvents(ctx context.Context, onConnect func(), handle func(ChangeEvent)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/int/api/v1/events", nil)
+
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("X-Cluster-ID", c.clusterID)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.streamClient.Do(req)
+
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+
+		json.NewDecoder(resp.Body).Decode(&errResp)
+
+		return fmt.Errorf("api error (%d): %s", resp.StatusCode, errResp.Error)
+	}
+
+	defer c.lastEventAt.Store(0)
+
+	onConnect()
+
+	// Cancelling the request unblocks the scanner when the connection silently died
+	idle := time.AfterFunc(eventStreamIdleTimeout, cancel)
+	defer idle.Stop()
+
+	var eventType, data string
+
+	scanner := bufio.NewScanner(resp.Body)
+
+	for scanner.Scan() {
+		idle.Reset(eventStreamIdleTimeout)
+
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			if eventType != "" {
+				c.lastEventAt.Store(time.Now().UnixNano())
+
+				if eventType != ChangeEventHeartbeat {
+					event := ChangeEvent{Type: eventType}
+					json.Unmarshal([]byte(data), &event)
+					handle(event)
+				}
+			}
+
+			eventType, data = "", ""
+		case strings.HasPrefix(line, "event:"):
+			eventType = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("event stream failed: %w", err)
+	}
+
+	return fmt.Errorf("event stream closed")
+}
+
+// Streaming reports whether change events are currently being delivered, meaning the
+// stream is connected and an event or heartbeat arrived recently. A connected stream
+// whose API lost its Postgres listener stops heartbeating and so stops counting.
+func (c *Client) Streaming() bool {
+	last := c.lastEventAt.Load()
+
+	return last != 0 && time.Since(time.Unix(0, last)) < eventStreamStaleAfter
+}
@@ -3,15 +3,24 @@ package config
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/sethvargo/go-envconfig"
 )
 
 // APIConfig is used by cmd/api
 type APIConfig struct {
-	DBURL       string `env:"KONTROL_DB_URL,required"`
-	ServerPort  string `env:"KONTROL_SERVER_PORT,default=8080"`
-	AutoMigrate bool   `env:"KONTROL_AUTO_MIGRATE,default=false"`
+	DBURL               string        `env:"KONTROL_DB_URL,required"`
+	ServerPort          string        `env:"KONTROL_SERVER_PORT,default=8080"`
+	AutoMigrate         bool          `env:"KONTROL_AUTO_MIGRATE,default=false"`
+	AdminBootstrapToken string        `env:"KONTROL_ADMIN_BOOTSTRAP_TOKEN"`      // created with admin scope when no admin tokens exist
+	AuthCacheTTL        time.Duration `env:"KONTROL_AUTH_CACHE_TTL,default=60s"` // 0 disables the verified api key cache
+	AuthCacheSize       int           `env:"KONTROL_AUTH_CACHE_SIZE,default=10000"`
+
+	RetryBackoffBase time.Duration `env:"KONTROL_RETRY_BACKOFF_BASE,default=10s"` // delay after the first failed apply, doubled per failure
+	RetryBackoffMax  time.Duration `env:"KONTROL_RETRY_BACKOFF_MAX,default=10m"`
+	RetryBudget      int           `env:"KONTROL_RETRY_BUDGET,default=0"`        // failures before a generation is parked; 0 retries forever
+	SelfHealInterval time.Duration `env:"KONTROL_SELF_HEAL_INTERVAL,default=1m"` // minimum time between two self-heals of a resource
 }
 
 // WorkerConfig is used by cmd/worker
@@ -20,7 +29,22 @@ type WorkerConfig struct {
 	APIKey        string `env:"KONTROL_API_KEY,required"`
 	ClusterID     string `env:"KONTROL_CLUSTER_ID,required"`
 	Kubeconfig    string `env:"KONTROL_KUBECONFIG"`
-	SupportedGVRs string `env:"KONTROL_SUPPORTED_GVRS"` // comma-separated list: deployment,pod,service
+	SupportedGVRs string `env:"KONTROL_SUPPORTED_GVRS"` // comma-separated list: deployment,pod,service,certificates.cert-manager.io
+
+	DiscoveryRefreshInterval time.Duration `env:"KONTROL_DISCOVERY_REFRESH_INTERVAL,default=5m"` // 0 disables periodic refresh
+	WatchResyncInterval      time.Duration `env:"KONTROL_WATCH_RESYNC_INTERVAL,default=10m"`     // 0 disables informer resync
+	WatchNamespaces          string        `env:"KONTROL_WATCH_NAMESPACES"`                      // comma-separated; empty watches all namespaces
+	ReconcileConcurrency     int           `env:"KONTROL_RECONCILE_CONCURRENCY,default=10"`      // resources applied in parallel
+	PollInterval             time.Duration `env:"KONTROL_POLL_INTERVAL,default=10s"`             // polling while the change event stream is down
+	StreamPollInterval       time.Duration `env:"KONTROL_STREAM_POLL_INTERVAL,default=1m"`       // fallback polling while change events are streaming
+	ShutdownDrainTimeout     time.Duration `env:"KONTROL_SHUTDOWN_DRAIN_TIMEOUT,default=25s"`    // keep below the pod's terminationGracePeriodSeconds
+
+	WatchRedactFields string `env:"KONTROL_WATCH_REDACT_FIELDS,default=Secret:data,Secret:stringData"` // Kind:field.path list masked before upload
+
+	LeaderElection     bool          `env:"KONTROL_LEADER_ELECTION,default=true"` // only the lease holder reconciles; standbys keep warm caches
+	LeaseDuration      time.Duration `env:"KONTROL_LEASE_DURATION,default=15s"`
+	LeaseRenewInterval time.Duration `env:"KONTROL_LEASE_RENEW_INTERVAL,default=5s"`
+	WorkerID           string        `env:"KONTROL_WORKER_ID"` // lease holder identity; defaults to hostname plus a random suffix
 }
 
 func LoadAPIConfig(ctx context.Context) *APIConfig {
@@ -30,11 +30,14 @@ func AutoMigrate(db *gorm.DB) error {
 	err := db.AutoMigrate(
 		&models.Cluster{},
 		&models.ClusterAPIKey{},
+		&models.AdminToken{},
 		&models.Resource{},
 		&models.ResourceCurrentState{},
 		&models.ResourceAppliedState{},
 		&models.GlobalResource{},
 		&models.GlobalResourceSyncedState{},
+		&models.ResourceRevision{},
+		&models.GlobalResourceRevision{},
 	)
 
 	if err != nil {
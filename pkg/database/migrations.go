@@ -21,17 +21,49 @@ func RunMigrations(db *gorm.DB) error {
 		return err
 	}
 
+	err = createRevisionHistoryTriggers(db)
+
+	if err != nil {
+		return err
+	}
+
+	err = backfillRevisionHistory(db)
+
+	if err != nil {
+		return err
+	}
+
+	err = createClusterSelectorFunction(db)
+
+	if err != nil {
+		return err
+	}
+
 	err = createUniqueIndexes(db)
 
 	if err != nil {
 		return err
 	}
 
+	err = linkDerivedResources(db)
+
+	if err != nil {
+		return err
+	}
+
 	log.Println("Database migrations completed")
 
 	return nil
 }
 
+// ChangesChannel is the Postgres NOTIFY channel the generation triggers publish to
+const ChangesChannel = "kontrol_changes"
+
+// createGenerationTrigger bumps the generation of a resource or global resource whenever
+// its spec, revision, deletion or (global resources only) overrides change, and notifies ChangesChannel on every insert and
+// generation bump. Inserts notify from an AFTER trigger so that upserts hitting an existing
+// row do not. The payload carries the table, the row ID and, for resources, the cluster ID;
+// notifications are only delivered once the transaction commits.
 func createGenerationTrigger(db *gorm.DB) error {
 	functionSQL := `
 CREATE OR REPLACE FUNCTION increment_resource_generation()
@@ -40,10 +72,18 @@ BEGIN
     IF TG_OP = 'UPDATE' THEN
         IF (NEW.desired_spec IS DISTINCT FROM OLD.desired_spec) OR
            (NEW.revision IS DISTINCT FROM OLD.revision) OR
-           (NEW.deleted_at IS DISTINCT FROM OLD.deleted_at) THEN
+           (NEW.deleted_at IS DISTINCT FROM OLD.deleted_at) OR
+           (to_jsonb(NEW)->'overrides' IS DISTINCT FROM to_jsonb(OLD)->'overrides') THEN
             NEW.generation := OLD.generation + 1;
+        ELSE
+            RETURN NEW;
         END IF;
     END IF;
+    PERFORM pg_notify('` + ChangesChannel + `', json_build_object(
+        'table', TG_TABLE_NAME,
+        'id', NEW.id,
+        'cluster_id', to_jsonb(NEW)->>'cluster_id'
+    )::text);
     RETURN NEW;
 END;
 $$ LANGUAGE plpgsql;
@@ -61,6 +101,12 @@ CREATE TRIGGER k_resources_increment_generation
     BEFORE UPDATE ON k_resources
     FOR EACH ROW
     EXECUTE FUNCTION increment_resource_generation();
+
+DROP TRIGGER IF EXISTS k_resources_notify_insert ON k_resources;
+CREATE TRIGGER k_resources_notify_insert
+    AFTER INSERT ON k_resources
+    FOR EACH ROW
+    EXECUTE FUNCTION increment_resource_generation();
 `
 
 	err = db.Exec(triggerSQL).Error
@@ -81,6 +127,12 @@ CREATE TRIGGER k_global_resources_increment_generation
     BEFORE UPDATE ON k_global_resources
     FOR EACH ROW
     EXECUTE FUNCTION increment_resource_generation();
+
+DROP TRIGGER IF EXISTS k_global_resources_notify_insert ON k_global_resources;
+CREATE TRIGGER k_global_resources_notify_insert
+    AFTER INSERT ON k_global_resources
+    FOR EACH ROW
+    EXECUTE FUNCTION increment_resource_generation();
 `
 
 	err := db.Exec(triggerSQL).Error
@@ -94,6 +146,178 @@ CREATE TRIGGER k_global_resources_increment_generation
 	return nil
 }
 
+// createRevisionHistoryTriggers records every insert and every spec or revision change of
+// resources and global resources in their revision tables. The author is read from the
+// transaction-local kontrol.author setting and defaults to "system".
+func createRevisionHistoryTriggers(db *gorm.DB) error {
+	resourceFunctionSQL := `
+CREATE OR REPLACE FUNCTION record_resource_revision()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' OR
+       (NEW.desired_spec IS DISTINCT FROM OLD.desired_spec) OR
+       (NEW.revision IS DISTINCT FROM OLD.revision) THEN
+        INSERT INTO k_resource_revisions (id, resource_id, desired_spec, generation, revision, author, created_at)
+        VALUES (gen_random_uuid(), NEW.id, NEW.desired_spec, NEW.generation, NEW.revision,
+                COALESCE(NULLIF(current_setting('kontrol.author', true), ''), 'system'), NOW());
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS k_resources_record_revision ON k_resources;
+CREATE TRIGGER k_resources_record_revision
+    AFTER INSERT OR UPDATE ON k_resources
+    FOR EACH ROW
+    EXECUTE FUNCTION record_resource_revision();
+`
+
+	err := db.Exec(resourceFunctionSQL).Error
+
+	if err != nil {
+		return err
+	}
+
+	globalResourceFunctionSQL := `
+CREATE OR REPLACE FUNCTION record_global_resource_revision()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' OR
+       (NEW.desired_spec IS DISTINCT FROM OLD.desired_spec) OR
+       (NEW.revision IS DISTINCT FROM OLD.revision) THEN
+        INSERT INTO k_global_resource_revisions (id, global_resource_id, desired_spec, generation, revision, author, created_at)
+        VALUES (gen_random_uuid(), NEW.id, NEW.desired_spec, NEW.generation, NEW.revision,
+                COALESCE(NULLIF(current_setting('kontrol.author', true), ''), 'system'), NOW());
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS k_global_resources_record_revision ON k_global_resources;
+CREATE TRIGGER k_global_resources_record_revision
+    AFTER INSERT OR UPDATE ON k_global_resources
+    FOR EACH ROW
+    EXECUTE FUNCTION record_global_resource_revision();
+`
+
+	err = db.Exec(globalResourceFunctionSQL).Error
+
+	if err != nil {
+		return err
+	}
+
+	log.Println("Revision history triggers created")
+
+	return nil
+}
+
+// backfillRevisionHistory records the current spec of resources and global resources
+// created before the revision history triggers existed, so they can be rolled back to it.
+// Rows that already have history are left alone, which makes it idempotent.
+func backfillRevisionHistory(db *gorm.DB) error {
+	resourceBackfillSQL := `
+INSERT INTO k_resource_revisions (id, resource_id, desired_spec, generation, revision, author, created_at)
+SELECT gen_random_uuid(), r.id, r.desired_spec, r.generation, r.revision, 'system', r.updated_at
+FROM k_resources r
+WHERE r.deleted_at IS NULL
+AND NOT EXISTS (SELECT 1 FROM k_resource_revisions rr WHERE rr.resource_id = r.id);
+`
+
+	err := db.Exec(resourceBackfillSQL).Error
+
+	if err != nil {
+		return err
+	}
+
+	globalResourceBackfillSQL := `
+INSERT INTO k_global_resource_revisions (id, global_resource_id, desired_spec, generation, revision, author, created_at)
+SELECT gen_random_uuid(), gr.id, gr.desired_spec, gr.generation, gr.revision, 'system', gr.updated_at
+FROM k_global_resources gr
+WHERE gr.deleted_at IS NULL
+AND NOT EXISTS (SELECT 1 FROM k_global_resource_revisions grr WHERE grr.global_resource_id = gr.id);
+`
+
+	err = db.Exec(globalResourceBackfillSQL).Error
+
+	if err != nil {
+		return err
+	}
+
+	log.Println("Revision history backfilled")
+
+	return nil
+}
+
+// createClusterSelectorFunction creates kontrol_selector_matches(selector, labels), which
+// evaluates a global resource cluster selector against the labels of a cluster. A NULL
+// selector matches every cluster.
+func createClusterSelectorFunction(db *gorm.DB) error {
+	functionSQL := `
+CREATE OR REPLACE FUNCTION kontrol_selector_matches(selector jsonb, labels jsonb)
+RETURNS boolean AS $$
+DECLARE
+    expr jsonb;
+    label_value text;
+    label_values text[];
+BEGIN
+    IF selector IS NULL OR jsonb_typeof(selector) <> 'object' THEN
+        RETURN TRUE;
+    END IF;
+
+    IF labels IS NULL OR jsonb_typeof(labels) <> 'object' THEN
+        labels := '{}'::jsonb;
+    END IF;
+
+    IF jsonb_typeof(selector->'match_labels') = 'object' AND NOT labels @> (selector->'match_labels') THEN
+        RETURN FALSE;
+    END IF;
+
+    IF jsonb_typeof(selector->'match_expressions') IS DISTINCT FROM 'array' THEN
+        RETURN TRUE;
+    END IF;
+
+    FOR expr IN SELECT jsonb_array_elements(selector->'match_expressions') LOOP
+        label_value := labels->>(expr->>'key');
+        label_values := ARRAY(SELECT jsonb_array_elements_text(COALESCE(expr->'values', '[]'::jsonb)));
+
+        CASE expr->>'operator'
+            WHEN 'In' THEN
+                IF label_value IS NULL OR NOT (label_value = ANY (label_values)) THEN
+                    RETURN FALSE;
+                END IF;
+            WHEN 'NotIn' THEN
+                IF label_value IS NOT NULL AND label_value = ANY (label_values) THEN
+                    RETURN FALSE;
+                END IF;
+            WHEN 'Exists' THEN
+                IF label_value IS NULL THEN
+                    RETURN FALSE;
+                END IF;
+            WHEN 'DoesNotExist' THEN
+                IF label_value IS NOT NULL THEN
+                    RETURN FALSE;
+                END IF;
+            ELSE
+                RETURN FALSE;
+        END CASE;
+    END LOOP;
+
+    RETURN TRUE;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+`
+
+	err := db.Exec(functionSQL).Error
+
+	if err != nil {
+		return err
+	}
+
+	log.Println("Cluster selector function created")
+
+	return nil
+}
+
 func createUniqueIndexes(db *gorm.DB) error {
 	resourceIndexSQL := `
 CREATE UNIQUE INDEX IF NOT EXISTS idx_k_resources_unique_key
@@ -123,3 +347,34 @@ WHERE deleted_at IS NULL;
 
 	return nil
 }
+
+// linkDerivedResources links resources derived from a global resource before
+// k_resources.global_resource_id existed back to it, matching by key in the clusters
+// that synced it. Linking changes neither spec nor revision, so no generation is bumped.
+func linkDerivedResources(db *gorm.DB) error {
+	linkSQL := `
+UPDATE k_resources r
+SET global_resource_id = gr.id
+FROM k_global_resources gr
+JOIN k_global_resource_synced_states ss
+    ON ss.global_resource_id = gr.id
+    AND ss.deleted_at IS NULL
+WHERE r.global_resource_id IS NULL
+AND r.deleted_at IS NULL
+AND gr.deleted_at IS NULL
+AND ss.cluster_id = r.cluster_id
+AND r.namespace = gr.namespace
+AND r.kind = gr.kind
+AND r.name = gr.name;
+`
+
+	err := db.Exec(linkSQL).Error
+
+	if err != nil {
+		return err
+	}
+
+	log.Println("Derived resources linked")
+
+	return nil
+}
@@ -12,12 +12,20 @@ import (
 type GlobalSyncer struct {
 	Client    *apiclient.Client
 	ClusterID string
+
+	PollInterval       time.Duration // delay between passes while the change event stream is down
+	StreamPollInterval time.Duration // delay between passes while change events are streaming
+
+	wake chan struct{}
 }
 
-func NewGlobalSyncer(client *apiclient.Client, clusterID string) *GlobalSyncer {
+func NewGlobalSyncer(client *apiclient.Client, clusterID string, pollInterval, streamPollInterval time.Duration) *GlobalSyncer {
 	return &GlobalSyncer{
-		Client:    client,
-		ClusterID: clusterID,
+		Client:             client,
+		ClusterID:          clusterID,
+		PollInterval:       pollInterval,
+		StreamPollInterval: streamPollInterval,
+		wake:               make(chan struct{}, 1),
 	}
 }
 
@@ -27,17 +35,39 @@ func (g *GlobalSyncer) Start(ctx context.Context) {
 	g.sync(ctx)
 
 	for {
+		interval := g.PollInterval
+
+		// Polling slows down while change events are streaming, since they wake the loop anyway
+		if g.Client.Streaming() {
+			interval = g.StreamPollInterval
+		}
+
 		select {
 		case <-ctx.Done():
 			log.Println("[GlobalSyncer] Stopping global resource sync loop")
 			return
-		case <-time.After(10 * time.Second):
+		case <-g.wake:
+			g.sync(ctx)
+		case <-time.After(interval):
 			g.sync(ctx)
 		}
 	}
 }
 
+// Wake starts the next sync pass right away instead of after the poll interval
+func (g *GlobalSyncer) Wake() {
+	select {
+	case g.wake <- struct{}{}:
+	default:
+	}
+}
+
+// sync handles one page of out-of-sync and deleted global resources. Once ctx is
+// cancelled no new item is started, but the current one runs to completion so its
+// resource and synced state are not left half-written.
 func (g *GlobalSyncer) sync(ctx context.Context) {
+	itemCtx := context.WithoutCancel(ctx)
+
 	// Fetch out-of-sync global resources from API
 	globalResources, err := g.Client.ListOutOfSyncGlobalResources(ctx, 100)
 
@@ -47,7 +77,11 @@ func (g *GlobalSyncer) sync(ctx context.Context) {
 	}
 
 	for _, gr := range globalResources {
-		g.syncGlobalResource(ctx, &gr)
+		if ctx.Err() != nil {
+			return
+		}
+
+		g.syncGlobalResource(itemCtx, &gr)
 	}
 
 	// Fetch deleted global resources from API
@@ -59,28 +93,50 @@ func (g *GlobalSyncer) sync(ctx context.Context) {
 	}
 
 	for _, gr := range deletedGlobalResources {
-		g.cleanupDeletedGlobalResource(ctx, &gr)
+		if ctx.Err() != nil {
+			return
+		}
+
+		g.cleanupGlobalResource(itemCtx, &gr)
+	}
+
+	// Fetch global resources whose cluster selector no longer matches this cluster
+	unmatchedGlobalResources, err := g.Client.ListUnmatchedGlobalResources(ctx, 100)
+
+	if err != nil {
+		log.Printf("[GlobalSyncer] Failed to fetch unmatched global resources: %v", err)
+		return
+	}
+
+	for _, gr := range unmatchedGlobalResources {
+		if ctx.Err() != nil {
+			return
+		}
+
+		g.cleanupGlobalResource(itemCtx, &gr)
 	}
 }
 
 func (g *GlobalSyncer) syncGlobalResource(ctx context.Context, gr *apiclient.GlobalResourceForSync) {
-	// Create resource for this cluster
-	_, err := g.Client.CreateResource(ctx, &apiclient.CreateResourceRequest{
-		Namespace:   gr.Namespace,
-		Kind:        gr.Kind,
-		Name:        gr.Name,
-		APIVersion:  gr.APIVersion,
-		DesiredSpec: gr.DesiredSpec,
-		Revision:    gr.Revision,
+	// Create or update the derived resource for this cluster
+	_, err := g.Client.UpsertResource(ctx, &apiclient.UpsertResourceRequest{
+		GlobalResourceID: gr.ID,
+		Namespace:        gr.Namespace,
+		Kind:             gr.Kind,
+		Name:             gr.Name,
+		APIVersion:       gr.APIVersion,
+		DesiredSpec:      gr.DesiredSpec,
+		Revision:         gr.Revision,
+		SelfHeal:         gr.SelfHeal,
 	})
 
 	if err != nil {
-		log.Printf("[GlobalSyncer] Failed to create resource for global resource %s: %v", gr.ID, err)
+		log.Printf("[GlobalSyncer] Failed to upsert resource for global resource %s: %v", gr.ID, err)
 		return
 	}
 
 	// Update synced state
-	err = g.Client.UpsertSyncedState(ctx, gr.ID, gr.Generation)
+	err = g.Client.UpsertSyncedState(ctx, gr.ID, gr.Generation, gr.ClusterLabels)
 
 	if err != nil {
 		log.Printf("[GlobalSyncer] Failed to update synced state for global resource %s: %v", gr.ID, err)
@@ -90,9 +146,11 @@ func (g *GlobalSyncer) syncGlobalResource(ctx context.Context, gr *apiclient.Glo
 	log.Printf("[GlobalSyncer] Synced global resource %s to cluster %s (gen=%d)", gr.ID, g.ClusterID, gr.Generation)
 }
 
-func (g *GlobalSyncer) cleanupDeletedGlobalResource(ctx context.Context, gr *models.GlobalResource) {
-	// Soft-delete the resource for this cluster
-	err := g.Client.SoftDeleteResourceByKey(ctx, gr.Namespace, gr.Kind, gr.Name)
+// cleanupGlobalResource removes the resource derived from a global resource that was
+// deleted or no longer targets this cluster
+func (g *GlobalSyncer) cleanupGlobalResource(ctx context.Context, gr *models.GlobalResource) {
+	// Soft-delete the resource derived for this cluster, leaving a local one with the same key alone
+	err := g.Client.DeleteDerivedResource(ctx, gr.ID)
 
 	if err != nil {
 		log.Printf("[GlobalSyncer] Failed to delete resource for global resource %s: %v", gr.ID, err)
@@ -107,5 +165,5 @@ func (g *GlobalSyncer) cleanupDeletedGlobalResource(ctx context.Context, gr *mod
 		return
 	}
 
-	log.Printf("[GlobalSyncer] Cleaned up deleted global resource %s from cluster %s", gr.ID, g.ClusterID)
+	log.Printf("[GlobalSyncer] Cleaned up global resource %s from cluster %s", gr.ID, g.ClusterID)
 }
@@ -0,0 +1,221 @@
+package k8s
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// maxDriftEntries caps the number of differences reported by DetectDrift
+const maxDriftEntries = 20
+
+// driftIgnoredFields are top-level fields that are not part of the desired state
+var driftIgnoredFields = map[string]bool{
+	"apiVersion": true,
+	"kind":       true,
+	"metadata":   true,
+	"status":     true,
+}
+
+// DetectDrift compares the desired object of a resource of the given kind against the
+// live object and returns one
+// human-readable entry per difference, e.g. `spec.replicas: desired 3, live 5`.
+//
+// Every top-level field of desired except apiVersion, kind, metadata and status is
+// compared, so ConfigMap data and RBAC rules drift as well as specs. Only fields set in
+// desired are compared: fields that exist only in live are assumed to be defaulted by the
+// API server or set by other controllers and are ignored. Lists are compared element by
+// element and must have the same length. Numbers and quantities are compared by value
+// ("500m" equals 0.5), and fields redacted by the watcher are skipped. Secret stringData
+// is compared as the data it is written to; the kind is passed separately since stored
+// desired specs do not carry it.
+func DetectDrift(kind string, desiredObject, liveObject []byte) ([]string, error) {
+	var desired map[string]interface{}
+
+	err := json.Unmarshal(desiredObject, &desired)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal desired object: %w", err)
+	}
+
+	var live map[string]interface{}
+
+	if len(liveObject) > 0 {
+		err = json.Unmarshal(liveObject, &live)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal live object: %w", err)
+		}
+	}
+
+	if kind == "Secret" {
+		mergeStringData(desired)
+	}
+
+	keys := make([]string, 0, len(desired))
+
+	for key := range desired {
+		if !driftIgnoredFields[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	var diffs []string
+
+	for _, key := range keys {
+		liveValue, exists := live[key]
+
+		if !exists {
+			if desired[key] != nil {
+				diffs = append(diffs, fmt.Sprintf("%s: missing in live", key))
+			}
+			continue
+		}
+
+		diffValue(key, desired[key], liveValue, &diffs)
+	}
+
+	if len(diffs) > maxDriftEntries {
+		diffs = append(diffs[:maxDriftEntries], fmt.Sprintf("... and %d more", len(diffs)-maxDriftEntries))
+	}
+
+	return diffs, nil
+}
+
+// mergeStringData moves the stringData of a desired Secret into data, base64-encoded,
+// since the API server never returns stringData
+func mergeStringData(secret map[string]interface{}) {
+	stringData, ok := secret["stringData"].(map[string]interface{})
+
+	if !ok {
+		return
+	}
+
+	data, ok := secret["data"].(map[string]interface{})
+
+	if !ok {
+		data = make(map[string]interface{})
+	}
+
+	for key, value := range stringData {
+		if str, ok := value.(string); ok {
+			data[key] = base64.StdEncoding.EncodeToString([]byte(str))
+		}
+	}
+
+	secret["data"] = data
+	delete(secret, "stringData")
+}
+
+func diffValue(path string, desired, live interface{}, diffs *[]string) {
+	// Redacted live values cannot be compared
+	if live == RedactedValue {
+		return
+	}
+
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+
+		if !ok {
+			*diffs = append(*diffs, fmt.Sprintf("%s: desired %s, live %s", path, formatValue(desired), formatValue(live)))
+			return
+		}
+
+		keys := make([]string, 0, len(d))
+
+		for key := range d {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			liveValue, exists := l[key]
+
+			if !exists {
+				// A desired null is satisfied by an absent field
+				if d[key] != nil {
+					*diffs = append(*diffs, fmt.Sprintf("%s.%s: missing in live", path, key))
+				}
+				continue
+			}
+
+			diffValue(path+"."+key, d[key], liveValue, diffs)
+		}
+	case []interface{}:
+		l, ok := live.([]interface{})
+
+		if !ok || len(l) != len(d) {
+			*diffs = append(*diffs, fmt.Sprintf("%s: desired %s, live %s", path, formatValue(desired), formatValue(live)))
+			return
+		}
+
+		for i := range d {
+			diffValue(fmt.Sprintf("%s[%d]", path, i), d[i], l[i], diffs)
+		}
+	default:
+		if !reflect.DeepEqual(desired, live) && !sameQuantity(desired, live) {
+			*diffs = append(*diffs, fmt.Sprintf("%s: desired %s, live %s", path, formatValue(desired), formatValue(live)))
+		}
+	}
+}
+
+// sameQuantity reports whether two scalars are the same number or Kubernetes quantity,
+// e.g. "500m" and 0.5, or "1Gi" and "1024Mi", as the API server canonicalizes quantities
+func sameQuantity(desired, live interface{}) bool {
+	desiredQuantity, ok := parseQuantity(desired)
+
+	if !ok {
+		return false
+	}
+
+	liveQuantity, ok := parseQuantity(live)
+
+	if !ok {
+		return false
+	}
+
+	return desiredQuantity.Cmp(liveQuantity) == 0
+}
+
+func parseQuantity(v interface{}) (resource.Quantity, bool) {
+	var str string
+
+	switch value := v.(type) {
+	case string:
+		str = value
+	case float64:
+		str = strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return resource.Quantity{}, false
+	}
+
+	quantity, err := resource.ParseQuantity(str)
+
+	if err != nil {
+		return resource.Quantity{}, false
+	}
+
+	return quantity, true
+}
+
+func formatValue(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return fmt.Sprintf("list of %d", len(v.([]interface{})))
+	}
+
+	b, _ := json.Marshal(v)
+
+	return string(b)
+}
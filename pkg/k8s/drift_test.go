@@ -0,0 +1,156 @@
+package k8s
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDetectDrift(t *testing.T) {
+	// Desired specs are stored without apiVersion and kind, like in k_resources
+	tests := []struct {
+		name    string
+		kind    string
+		desired string
+		live    string
+		want    []string
+	}{
+		{
+			name:    "in sync",
+			kind:    "Deployment",
+			desired: `{"metadata":{"name":"a"},"spec":{"replicas":3}}`,
+			live:    `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"a","uid":"x"},"spec":{"replicas":3,"paused":false},"status":{"replicas":3}}`,
+		},
+		{
+			name:    "spec field changed",
+			kind:    "Deployment",
+			desired: `{"spec":{"replicas":3}}`,
+			live:    `{"kind":"Deployment","spec":{"replicas":5}}`,
+			want:    []string{"spec.replicas: desired 3, live 5"},
+		},
+		{
+			name:    "metadata and status are ignored",
+			kind:    "Deployment",
+			desired: `{"metadata":{"labels":{"a":"b"}},"status":{"replicas":1}}`,
+			live:    `{"kind":"Deployment","metadata":{"labels":{"a":"c"}},"status":{"replicas":2}}`,
+		},
+		{
+			name:    "configmap data changed",
+			kind:    "ConfigMap",
+			desired: `{"data":{"key":"one"}}`,
+			live:    `{"kind":"ConfigMap","data":{"key":"two"}}`,
+			want:    []string{`data.key: desired "one", live "two"`},
+		},
+		{
+			name:    "top-level field missing in live",
+			kind:    "ConfigMap",
+			desired: `{"data":{"key":"one"}}`,
+			live:    `{"kind":"ConfigMap"}`,
+			want:    []string{"data: missing in live"},
+		},
+		{
+			name:    "rbac rules changed",
+			kind:    "Role",
+			desired: `{"rules":[{"verbs":["get","list"]}]}`,
+			live:    `{"kind":"Role","rules":[{"verbs":["get"]}]}`,
+			want:    []string{"rules[0].verbs: desired list of 2, live list of 1"},
+		},
+		{
+			name:    "int and float numbers",
+			kind:    "Deployment",
+			desired: `{"spec":{"replicas":3}}`,
+			live:    `{"spec":{"replicas":3.0}}`,
+		},
+		{
+			name:    "cpu quantity canonicalized",
+			kind:    "Deployment",
+			desired: `{"spec":{"cpu":0.5,"limit":"0.5"}}`,
+			live:    `{"spec":{"cpu":"500m","limit":"500m"}}`,
+		},
+		{
+			name:    "memory quantity canonicalized",
+			kind:    "Deployment",
+			desired: `{"spec":{"memory":"1024Mi"}}`,
+			live:    `{"spec":{"memory":"1Gi"}}`,
+		},
+		{
+			name:    "quantity changed",
+			kind:    "Deployment",
+			desired: `{"spec":{"memory":"1Gi"}}`,
+			live:    `{"spec":{"memory":"2Gi"}}`,
+			want:    []string{`spec.memory: desired "1Gi", live "2Gi"`},
+		},
+		{
+			name:    "non-quantity strings compared raw",
+			kind:    "Deployment",
+			desired: `{"spec":{"image":"nginx:1.25"}}`,
+			live:    `{"spec":{"image":"nginx:1.26"}}`,
+			want:    []string{`spec.image: desired "nginx:1.25", live "nginx:1.26"`},
+		},
+		{
+			name:    "redacted live values are skipped",
+			kind:    "Secret",
+			desired: `{"data":{"password":"c2VjcmV0"}}`,
+			live:    `{"kind":"Secret","data":{"password":"<redacted>"}}`,
+		},
+		{
+			name:    "secret stringData compared as data",
+			kind:    "Secret",
+			desired: `{"stringData":{"user":"admin"}}`,
+			live:    `{"kind":"Secret","data":{"user":"YWRtaW4="}}`,
+		},
+		{
+			name:    "secret stringData changed",
+			kind:    "Secret",
+			desired: `{"stringData":{"user":"admin"}}`,
+			live:    `{"kind":"Secret","data":{"user":"cm9vdA=="}}`,
+			want:    []string{`data.user: desired "YWRtaW4=", live "cm9vdA=="`},
+		},
+		{
+			name:    "secret stringData merged into data",
+			kind:    "Secret",
+			desired: `{"data":{"user":"YWRtaW4="},"stringData":{"pass":"secret"}}`,
+			live:    `{"kind":"Secret","data":{"user":"YWRtaW4=","pass":"c2VjcmV0"}}`,
+		},
+		{
+			name:    "stringData is only merged for secrets",
+			kind:    "Widget",
+			desired: `{"stringData":{"user":"admin"}}`,
+			live:    `{"kind":"Widget","data":{"user":"YWRtaW4="}}`,
+			want:    []string{"stringData: missing in live"},
+		},
+		{
+			name:    "desired null satisfied by absent field",
+			kind:    "Deployment",
+			desired: `{"spec":{"strategy":null}}`,
+			live:    `{"spec":{}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectDrift(tt.kind, []byte(tt.desired), []byte(tt.live))
+
+			if err != nil {
+				t.Fatalf("DetectDrift() error = %v", err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("DetectDrift() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectDriftInvalidJSON(t *testing.T) {
+	_, err := DetectDrift("ConfigMap", []byte(`{`), []byte(`{}`))
+
+	if err == nil {
+		t.Error("DetectDrift() expected error for invalid desired object")
+	}
+
+	_, err = DetectDrift("ConfigMap", []byte(`{}`), []byte(`{`))
+
+	if err == nil {
+		t.Error("DetectDrift() expected error for invalid live object")
+	}
+}
@@ -40,12 +40,17 @@ var aliasToKind = map[string]string{
 	"cronjob":       "CronJob",
 }
 
-// SupportedGVRs holds the filtered list of GVRs to watch
+// SupportedGVRs holds the filtered list of built-in GVRs to watch
 var SupportedGVRs []schema.GroupVersionResource
 
+// SupportedResourceArgs holds kubectl-style resource arguments from the filter that are
+// not built-in aliases. They are resolved through discovery with GVRResolver.ResolveResourceArg.
+var SupportedResourceArgs []string
+
 // InitSupportedGVRs initializes SupportedGVRs based on the filter string.
-// If filter is empty, all GVRs are enabled.
-// Filter format: comma-separated lowercase names (e.g., "deployment,pod,service")
+// If filter is empty, all built-in GVRs are enabled.
+// Filter format: comma-separated lowercase aliases (e.g., "deployment,pod,service")
+// or kubectl-style resource arguments (e.g., "serviceaccounts", "certificates.v1.cert-manager.io")
 func InitSupportedGVRs(filter string) {
 	if filter == "" {
 		// No filter - enable all
@@ -58,17 +63,27 @@ func InitSupportedGVRs(filter string) {
 
 	parts := strings.Split(filter, ",")
 	SupportedGVRs = make([]schema.GroupVersionResource, 0, len(parts))
+	SupportedResourceArgs = nil
 
 	for _, part := range parts {
 		alias := strings.TrimSpace(strings.ToLower(part))
+		if alias == "" {
+			continue
+		}
+
 		if kind, ok := aliasToKind[alias]; ok {
 			if gvr, ok := gvrMapping[kind]; ok {
 				SupportedGVRs = append(SupportedGVRs, gvr)
 			}
+			continue
 		}
+
+		SupportedResourceArgs = append(SupportedResourceArgs, alias)
 	}
 }
 
+// GetGVR returns the built-in GVR for a Kind without consulting discovery.
+// Prefer GVRResolver.ResolveKind, which also handles CRDs and other API versions.
 func GetGVR(kind, apiVersion string) schema.GroupVersionResource {
 	if gvr, ok := gvrMapping[kind]; ok {
 		return gvr
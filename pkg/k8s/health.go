@@ -0,0 +1,216 @@
+package k8s
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Health values of a live object
+const (
+	HealthHealthy     = "healthy"
+	HealthProgressing = "progressing"
+	HealthDegraded    = "degraded"
+	HealthUnknown     = "unknown"
+)
+
+// AssessHealth evaluates whether a live object is actually running, not just applied.
+// Workloads must have finished rolling out, Jobs must have succeeded, LoadBalancer
+// Services must have an ingress, and any other kind is judged by its Ready or Available
+// condition. Objects without status (ConfigMap, Secret, ...) are healthy once they exist.
+func AssessHealth(obj *unstructured.Unstructured) (string, string) {
+	if observed, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration"); found && observed < obj.GetGeneration() {
+		return HealthProgressing, "waiting for the controller to observe the latest generation"
+	}
+
+	switch obj.GroupVersionKind().GroupKind().String() {
+	case "Deployment.apps":
+		return deploymentHealth(obj)
+	case "StatefulSet.apps":
+		return statefulSetHealth(obj)
+	case "DaemonSet.apps":
+		return daemonSetHealth(obj)
+	case "Job.batch":
+		return jobHealth(obj)
+	case "Service":
+		return serviceHealth(obj)
+	case "Pod":
+		return podHealth(obj)
+	case "PersistentVolumeClaim":
+		return pvcHealth(obj)
+	}
+
+	return conditionsHealth(obj)
+}
+
+func deploymentHealth(obj *unstructured.Unstructured) (string, string) {
+	if cond := findCondition(obj, "Progressing"); cond != nil && cond["status"] == "False" && cond["reason"] == "ProgressDeadlineExceeded" {
+		return HealthDegraded, fmt.Sprintf("rollout exceeded its progress deadline: %v", cond["message"])
+	}
+
+	replicas := specReplicas(obj)
+	updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+	available, _, _ := unstructured.NestedInt64(obj.Object, "status", "availableReplicas")
+	total, _, _ := unstructured.NestedInt64(obj.Object, "status", "replicas")
+
+	if updated < replicas {
+		return HealthProgressing, fmt.Sprintf("%d of %d replicas updated", updated, replicas)
+	}
+
+	if total > updated {
+		return HealthProgressing, fmt.Sprintf("%d old replicas pending termination", total-updated)
+	}
+
+	if available < replicas {
+		return HealthProgressing, fmt.Sprintf("%d of %d replicas available", available, replicas)
+	}
+
+	return HealthHealthy, ""
+}
+
+func statefulSetHealth(obj *unstructured.Unstructured) (string, string) {
+	replicas := specReplicas(obj)
+	ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+	updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+	strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+
+	if strategy != "OnDelete" {
+		currentRevision, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+		updateRevision, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+
+		if updated < replicas || currentRevision != updateRevision {
+			return HealthProgressing, fmt.Sprintf("%d of %d replicas updated", updated, replicas)
+		}
+	}
+
+	if ready < replicas {
+		return HealthProgressing, fmt.Sprintf("%d of %d replicas ready", ready, replicas)
+	}
+
+	return HealthHealthy, ""
+}
+
+func daemonSetHealth(obj *unstructured.Unstructured) (string, string) {
+	desired, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+	updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedNumberScheduled")
+	available, _, _ := unstructured.NestedInt64(obj.Object, "status", "numberAvailable")
+	strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+
+	if strategy != "OnDelete" && updated < desired {
+		return HealthProgressing, fmt.Sprintf("%d of %d pods updated", updated, desired)
+	}
+
+	if available < desired {
+		return HealthProgressing, fmt.Sprintf("%d of %d pods available", available, desired)
+	}
+
+	return HealthHealthy, ""
+}
+
+func jobHealth(obj *unstructured.Unstructured) (string, string) {
+	if cond := findCondition(obj, "Failed"); cond != nil && cond["status"] == "True" {
+		return HealthDegraded, fmt.Sprintf("job failed: %v", cond["message"])
+	}
+
+	if cond := findCondition(obj, "Complete"); cond != nil && cond["status"] == "True" {
+		return HealthHealthy, ""
+	}
+
+	return HealthProgressing, "job running"
+}
+
+func serviceHealth(obj *unstructured.Unstructured) (string, string) {
+	serviceType, _, _ := unstructured.NestedString(obj.Object, "spec", "type")
+
+	if serviceType != "LoadBalancer" {
+		return HealthHealthy, ""
+	}
+
+	ingress, _, _ := unstructured.NestedSlice(obj.Object, "status", "loadBalancer", "ingress")
+
+	if len(ingress) == 0 {
+		return HealthProgressing, "waiting for load balancer"
+	}
+
+	return HealthHealthy, ""
+}
+
+func podHealth(obj *unstructured.Unstructured) (string, string) {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+
+	switch phase {
+	case "Succeeded":
+		return HealthHealthy, ""
+	case "Failed":
+		message, _, _ := unstructured.NestedString(obj.Object, "status", "message")
+		return HealthDegraded, fmt.Sprintf("pod failed: %s", message)
+	case "Running":
+		if cond := findCondition(obj, "Ready"); cond != nil && cond["status"] == "True" {
+			return HealthHealthy, ""
+		}
+		return HealthProgressing, "pod not ready"
+	}
+
+	return HealthProgressing, fmt.Sprintf("pod %s", phase)
+}
+
+func pvcHealth(obj *unstructured.Unstructured) (string, string) {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+
+	switch phase {
+	case "Bound":
+		return HealthHealthy, ""
+	case "Lost":
+		return HealthDegraded, "claim lost its volume"
+	}
+
+	return HealthProgressing, "waiting for volume"
+}
+
+// conditionsHealth judges CRDs and other kinds by their Ready or Available condition
+func conditionsHealth(obj *unstructured.Unstructured) (string, string) {
+	for _, conditionType := range []string{"Ready", "Available"} {
+		cond := findCondition(obj, conditionType)
+
+		if cond == nil {
+			continue
+		}
+
+		switch cond["status"] {
+		case "True":
+			return HealthHealthy, ""
+		case "False":
+			return HealthDegraded, fmt.Sprintf("%s: %v", conditionType, cond["message"])
+		}
+
+		return HealthProgressing, fmt.Sprintf("%s condition is %v", conditionType, cond["status"])
+	}
+
+	return HealthHealthy, ""
+}
+
+// findCondition returns the status condition of the given type, or nil
+func findCondition(obj *unstructured.Unstructured, conditionType string) map[string]interface{} {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+
+		if ok && cond["type"] == conditionType {
+			return cond
+		}
+	}
+
+	return nil
+}
+
+// specReplicas returns spec.replicas, which defaults to 1 when unset
+func specReplicas(obj *unstructured.Unstructured) int64 {
+	replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+
+	if !found {
+		return 1
+	}
+
+	return replicas
+}
@@ -0,0 +1,156 @@
+package k8s
+
+import "testing"
+
+func TestAssessHealth(t *testing.T) {
+	tests := []struct {
+		name   string
+		object string
+		want   string
+	}{
+		{
+			name:   "deployment rolled out",
+			object: `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"generation":2},"spec":{"replicas":3},"status":{"observedGeneration":2,"replicas":3,"updatedReplicas":3,"availableReplicas":3}}`,
+			want:   HealthHealthy,
+		},
+		{
+			name:   "deployment generation not observed",
+			object: `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"generation":2},"spec":{"replicas":3},"status":{"observedGeneration":1,"replicas":3,"updatedReplicas":3,"availableReplicas":3}}`,
+			want:   HealthProgressing,
+		},
+		{
+			name:   "deployment replicas updating",
+			object: `{"apiVersion":"apps/v1","kind":"Deployment","spec":{"replicas":3},"status":{"replicas":3,"updatedReplicas":1,"availableReplicas":3}}`,
+			want:   HealthProgressing,
+		},
+		{
+			name:   "deployment old replicas terminating",
+			object: `{"apiVersion":"apps/v1","kind":"Deployment","spec":{"replicas":3},"status":{"replicas":4,"updatedReplicas":3,"availableReplicas":3}}`,
+			want:   HealthProgressing,
+		},
+		{
+			name:   "deployment progress deadline exceeded",
+			object: `{"apiVersion":"apps/v1","kind":"Deployment","spec":{"replicas":3},"status":{"conditions":[{"type":"Progressing","status":"False","reason":"ProgressDeadlineExceeded"}]}}`,
+			want:   HealthDegraded,
+		},
+		{
+			name:   "deployment replicas default to one",
+			object: `{"apiVersion":"apps/v1","kind":"Deployment","spec":{},"status":{"replicas":1,"updatedReplicas":1,"availableReplicas":1}}`,
+			want:   HealthHealthy,
+		},
+		{
+			name:   "statefulset revision pending",
+			object: `{"apiVersion":"apps/v1","kind":"StatefulSet","spec":{"replicas":2},"status":{"readyReplicas":2,"updatedReplicas":2,"currentRevision":"a","updateRevision":"b"}}`,
+			want:   HealthProgressing,
+		},
+		{
+			name:   "statefulset on delete only needs ready replicas",
+			object: `{"apiVersion":"apps/v1","kind":"StatefulSet","spec":{"replicas":2,"updateStrategy":{"type":"OnDelete"}},"status":{"readyReplicas":2,"currentRevision":"a","updateRevision":"b"}}`,
+			want:   HealthHealthy,
+		},
+		{
+			name:   "daemonset pods unavailable",
+			object: `{"apiVersion":"apps/v1","kind":"DaemonSet","status":{"desiredNumberScheduled":3,"updatedNumberScheduled":3,"numberAvailable":2}}`,
+			want:   HealthProgressing,
+		},
+		{
+			name:   "daemonset rolled out",
+			object: `{"apiVersion":"apps/v1","kind":"DaemonSet","status":{"desiredNumberScheduled":3,"updatedNumberScheduled":3,"numberAvailable":3}}`,
+			want:   HealthHealthy,
+		},
+		{
+			name:   "job complete",
+			object: `{"apiVersion":"batch/v1","kind":"Job","status":{"conditions":[{"type":"Complete","status":"True"}]}}`,
+			want:   HealthHealthy,
+		},
+		{
+			name:   "job failed",
+			object: `{"apiVersion":"batch/v1","kind":"Job","status":{"conditions":[{"type":"Failed","status":"True","message":"backoff limit"}]}}`,
+			want:   HealthDegraded,
+		},
+		{
+			name:   "job running",
+			object: `{"apiVersion":"batch/v1","kind":"Job","status":{}}`,
+			want:   HealthProgressing,
+		},
+		{
+			name:   "cluster ip service",
+			object: `{"apiVersion":"v1","kind":"Service","spec":{"type":"ClusterIP"}}`,
+			want:   HealthHealthy,
+		},
+		{
+			name:   "load balancer without ingress",
+			object: `{"apiVersion":"v1","kind":"Service","spec":{"type":"LoadBalancer"},"status":{"loadBalancer":{}}}`,
+			want:   HealthProgressing,
+		},
+		{
+			name:   "load balancer with ingress",
+			object: `{"apiVersion":"v1","kind":"Service","spec":{"type":"LoadBalancer"},"status":{"loadBalancer":{"ingress":[{"ip":"10.0.0.1"}]}}}`,
+			want:   HealthHealthy,
+		},
+		{
+			name:   "pod running and ready",
+			object: `{"apiVersion":"v1","kind":"Pod","status":{"phase":"Running","conditions":[{"type":"Ready","status":"True"}]}}`,
+			want:   HealthHealthy,
+		},
+		{
+			name:   "pod running not ready",
+			object: `{"apiVersion":"v1","kind":"Pod","status":{"phase":"Running","conditions":[{"type":"Ready","status":"False"}]}}`,
+			want:   HealthProgressing,
+		},
+		{
+			name:   "pod failed",
+			object: `{"apiVersion":"v1","kind":"Pod","status":{"phase":"Failed"}}`,
+			want:   HealthDegraded,
+		},
+		{
+			name:   "pvc bound",
+			object: `{"apiVersion":"v1","kind":"PersistentVolumeClaim","status":{"phase":"Bound"}}`,
+			want:   HealthHealthy,
+		},
+		{
+			name:   "pvc lost",
+			object: `{"apiVersion":"v1","kind":"PersistentVolumeClaim","status":{"phase":"Lost"}}`,
+			want:   HealthDegraded,
+		},
+		{
+			name:   "pvc pending",
+			object: `{"apiVersion":"v1","kind":"PersistentVolumeClaim","status":{"phase":"Pending"}}`,
+			want:   HealthProgressing,
+		},
+		{
+			name:   "custom resource ready",
+			object: `{"apiVersion":"example.com/v1","kind":"Widget","status":{"conditions":[{"type":"Ready","status":"True"}]}}`,
+			want:   HealthHealthy,
+		},
+		{
+			name:   "custom resource not ready",
+			object: `{"apiVersion":"example.com/v1","kind":"Widget","status":{"conditions":[{"type":"Ready","status":"False","message":"broken"}]}}`,
+			want:   HealthDegraded,
+		},
+		{
+			name:   "custom resource condition unknown",
+			object: `{"apiVersion":"example.com/v1","kind":"Widget","status":{"conditions":[{"type":"Available","status":"Unknown"}]}}`,
+			want:   HealthProgressing,
+		},
+		{
+			name:   "object without status",
+			object: `{"apiVersion":"v1","kind":"ConfigMap","data":{"key":"value"}}`,
+			want:   HealthHealthy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, message := AssessHealth(unmarshalObject(t, tt.object))
+
+			if got != tt.want {
+				t.Errorf("AssessHealth() = %q (%q), want %q", got, message, tt.want)
+			}
+
+			if got != HealthHealthy && message == "" {
+				t.Errorf("AssessHealth() returned %q without a message", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package k8s
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ManagedLabel marks objects applied by kontrol so watches can filter them server-side
+const ManagedLabel = "kontrol/managed"
+
+// ManagedLabelValue is the value of ManagedLabel on objects applied by kontrol
+const ManagedLabelValue = "true"
+
+// ManagedSelector returns the label selector matching objects applied by kontrol
+func ManagedSelector() string {
+	return labels.Set{ManagedLabel: ManagedLabelValue}.AsSelector().String()
+}
+
+// ParseNamespaces splits a comma-separated namespace list, dropping empty entries
+func ParseNamespaces(list string) []string {
+	var namespaces []string
+
+	for _, part := range strings.Split(list, ",") {
+		ns := strings.TrimSpace(part)
+
+		if ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+
+	return namespaces
+}
@@ -0,0 +1,82 @@
+package k8s
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RedactedValue replaces the value of redacted fields
+const RedactedValue = "<redacted>"
+
+// lastAppliedAnnotation holds a full copy of the object applied with kubectl apply
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// RedactRule redacts the field at Path of every object of Kind ("*" matches every kind)
+type RedactRule struct {
+	Kind string
+	Path []string
+}
+
+// ParseRedactRules parses a comma-separated list of Kind:field.path rules,
+// e.g. "Secret:data,Secret:stringData,ConfigMap:data.password"
+func ParseRedactRules(list string) []RedactRule {
+	var rules []RedactRule
+
+	for _, part := range strings.Split(list, ",") {
+		kind, path, ok := strings.Cut(strings.TrimSpace(part), ":")
+
+		if !ok || kind == "" || path == "" {
+			continue
+		}
+
+		rules = append(rules, RedactRule{Kind: kind, Path: strings.Split(path, ".")})
+	}
+
+	return rules
+}
+
+// Redact returns a copy of obj without managedFields and with every field matched by
+// rules replaced by RedactedValue. Maps keep their keys so consumers can still see
+// which entries exist. Objects with redacted fields also lose the kubectl
+// last-applied-configuration annotation, which would otherwise leak the same values.
+func Redact(obj *unstructured.Unstructured, rules []RedactRule) *unstructured.Unstructured {
+	redacted := obj.DeepCopy()
+	redacted.SetManagedFields(nil)
+
+	matched := false
+
+	for _, rule := range rules {
+		if rule.Kind != "*" && rule.Kind != obj.GetKind() {
+			continue
+		}
+
+		value, found, err := unstructured.NestedFieldNoCopy(redacted.Object, rule.Path...)
+
+		if err != nil || !found {
+			continue
+		}
+
+		matched = true
+
+		if fields, ok := value.(map[string]interface{}); ok {
+			for key := range fields {
+				fields[key] = RedactedValue
+			}
+			continue
+		}
+
+		unstructured.SetNestedField(redacted.Object, RedactedValue, rule.Path...)
+	}
+
+	if matched {
+		annotations := redacted.GetAnnotations()
+
+		if _, ok := annotations[lastAppliedAnnotation]; ok {
+			delete(annotations, lastAppliedAnnotation)
+			redacted.SetAnnotations(annotations)
+		}
+	}
+
+	return redacted
+}
@@ -0,0 +1,131 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseRedactRules(t *testing.T) {
+	tests := []struct {
+		name string
+		list string
+		want []RedactRule
+	}{
+		{
+			name: "empty",
+			list: "",
+		},
+		{
+			name: "defaults",
+			list: "Secret:data,Secret:stringData",
+			want: []RedactRule{
+				{Kind: "Secret", Path: []string{"data"}},
+				{Kind: "Secret", Path: []string{"stringData"}},
+			},
+		},
+		{
+			name: "nested path and wildcard kind",
+			list: " ConfigMap:data.password , *:spec.token ",
+			want: []RedactRule{
+				{Kind: "ConfigMap", Path: []string{"data", "password"}},
+				{Kind: "*", Path: []string{"spec", "token"}},
+			},
+		},
+		{
+			name: "malformed entries are skipped",
+			list: "Secret,:data,Secret:,,ConfigMap:data",
+			want: []RedactRule{
+				{Kind: "ConfigMap", Path: []string{"data"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseRedactRules(tt.list)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRedactRules(%q) = %v, want %v", tt.list, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	rules := ParseRedactRules("Secret:data,Secret:stringData,ConfigMap:data.password,*:spec.token")
+
+	tests := []struct {
+		name   string
+		object string
+		want   string
+	}{
+		{
+			name:   "secret data keeps keys",
+			object: `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"s"},"data":{"user":"YWRtaW4=","pass":"cGFzcw=="}}`,
+			want:   `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"s"},"data":{"user":"<redacted>","pass":"<redacted>"}}`,
+		},
+		{
+			name:   "nested scalar field",
+			object: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"c"},"data":{"password":"x","host":"db"}}`,
+			want:   `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"c"},"data":{"password":"<redacted>","host":"db"}}`,
+		},
+		{
+			name:   "wildcard kind",
+			object: `{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"w"},"spec":{"token":"t","size":1}}`,
+			want:   `{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"w"},"spec":{"token":"<redacted>","size":1}}`,
+		},
+		{
+			name:   "other kinds are untouched",
+			object: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"c"},"data":{"host":"db"}}`,
+			want:   `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"c"},"data":{"host":"db"}}`,
+		},
+		{
+			name:   "managed fields are dropped",
+			object: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"c","managedFields":[{"manager":"kubectl"}]}}`,
+			want:   `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"c"}}`,
+		},
+		{
+			name:   "last applied annotation dropped when redacted",
+			object: `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"s","annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{}","team":"a"}},"data":{"user":"YWRtaW4="}}`,
+			want:   `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"s","annotations":{"team":"a"}},"data":{"user":"<redacted>"}}`,
+		},
+		{
+			name:   "last applied annotation kept when nothing redacted",
+			object: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"c","annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{}"}},"data":{"host":"db"}}`,
+			want:   `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"c","annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{}"}},"data":{"host":"db"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := unmarshalObject(t, tt.object)
+			original := obj.DeepCopy()
+
+			got := Redact(obj, rules)
+
+			if want := unmarshalObject(t, tt.want); !reflect.DeepEqual(got.Object, want.Object) {
+				t.Errorf("Redact() = %v, want %v", got.Object, want.Object)
+			}
+
+			if !reflect.DeepEqual(obj.Object, original.Object) {
+				t.Errorf("Redact() modified its input: %v", obj.Object)
+			}
+		})
+	}
+}
+
+func unmarshalObject(t *testing.T, data string) *unstructured.Unstructured {
+	t.Helper()
+
+	obj := &unstructured.Unstructured{}
+
+	err := obj.UnmarshalJSON([]byte(data))
+
+	if err != nil {
+		t.Fatalf("failed to unmarshal object: %v", err)
+	}
+
+	return obj
+}
@@ -0,0 +1,177 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// minMissResetInterval limits how often a mapping miss may invalidate the
+// discovery cache, so a resource with a bad kind cannot hammer discovery.
+const minMissResetInterval = 30 * time.Second
+
+// GVRResolver resolves Kinds and partial resources to GVRs using the
+// cluster's discovery API. Discovery results are cached and refreshed
+// periodically, and on a mapping miss so newly installed CRDs are picked up.
+type GVRResolver struct {
+	mapper          *restmapper.DeferredDiscoveryRESTMapper
+	refreshInterval time.Duration
+
+	mu        sync.Mutex
+	lastReset time.Time
+}
+
+func NewGVRResolver(config *rest.Config, refreshInterval time.Duration) (*GVRResolver, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	return &GVRResolver{
+		mapper:          restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+		refreshInterval: refreshInterval,
+	}, nil
+}
+
+// Start periodically invalidates the discovery cache until ctx is cancelled
+func (r *GVRResolver) Start(ctx context.Context) {
+	if r.refreshInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.reset()
+		}
+	}
+}
+
+// ResolveKind returns the REST mapping for a Kind and apiVersion.
+// When apiVersion is empty, well-known kinds fall back to their built-in group/version.
+func (r *GVRResolver) ResolveKind(kind, apiVersion string) (*meta.RESTMapping, error) {
+	if apiVersion == "" {
+		if gvr, ok := gvrMapping[kind]; ok {
+			apiVersion = gvr.GroupVersion().String()
+		}
+	}
+
+	gv, err := schema.ParseGroupVersion(apiVersion)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid api version %q: %w", apiVersion, err)
+	}
+
+	gk := schema.GroupKind{Group: gv.Group, Kind: kind}
+
+	var versions []string
+
+	if gv.Version != "" {
+		versions = append(versions, gv.Version)
+	}
+
+	mapping, err := r.mapper.RESTMapping(gk, versions...)
+
+	if meta.IsNoMatchError(err) && r.resetOnMiss() {
+		mapping, err = r.mapper.RESTMapping(gk, versions...)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s %s: %w", apiVersion, kind, err)
+	}
+
+	return mapping, nil
+}
+
+// ResolveResource returns the REST mapping for a fully qualified GVR, which carries its scope
+func (r *GVRResolver) ResolveResource(gvr schema.GroupVersionResource) (*meta.RESTMapping, error) {
+	gvk, err := r.mapper.KindFor(gvr)
+
+	if meta.IsNoMatchError(err) && r.resetOnMiss() {
+		gvk, err = r.mapper.KindFor(gvr)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve kind for %s: %w", gvr.String(), err)
+	}
+
+	mapping, err := r.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve mapping for %s: %w", gvr.String(), err)
+	}
+
+	return mapping, nil
+}
+
+// ResolveResourceArg expands a kubectl-style resource argument such as "serviceaccounts",
+// "certificates.cert-manager.io" or "certificates.v1.cert-manager.io" to a full GVR
+func (r *GVRResolver) ResolveResourceArg(arg string) (schema.GroupVersionResource, error) {
+	gvr, gr := schema.ParseResourceArg(arg)
+
+	candidates := make([]schema.GroupVersionResource, 0, 2)
+
+	if gvr != nil {
+		candidates = append(candidates, *gvr)
+	}
+
+	candidates = append(candidates, gr.WithVersion(""))
+
+	for attempt := 0; attempt < 2; attempt++ {
+		for _, candidate := range candidates {
+			resolved, err := r.mapper.ResourceFor(candidate)
+
+			if err == nil {
+				return resolved, nil
+			}
+
+			if !meta.IsNoMatchError(err) {
+				return schema.GroupVersionResource{}, fmt.Errorf("failed to resolve resource %s: %w", arg, err)
+			}
+		}
+
+		if !r.resetOnMiss() {
+			break
+		}
+	}
+
+	return schema.GroupVersionResource{}, fmt.Errorf("failed to resolve resource %s: no matching resource in cluster", arg)
+}
+
+func (r *GVRResolver) resetOnMiss() bool {
+	r.mu.Lock()
+
+	if time.Since(r.lastReset) < minMissResetInterval {
+		r.mu.Unlock()
+		return false
+	}
+
+	r.mu.Unlock()
+	r.reset()
+
+	return true
+}
+
+func (r *GVRResolver) reset() {
+	r.mu.Lock()
+	r.lastReset = time.Now()
+	r.mu.Unlock()
+
+	r.mapper.Reset()
+	log.Println("[GVRResolver] Discovery cache invalidated")
+}
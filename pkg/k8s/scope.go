@@ -0,0 +1,89 @@
+package k8s
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// clusterScopedKinds lists well-known kinds that are not namespaced. The API server
+// has no discovery access to worker clusters, so it uses this list (together with
+// gvrMapping for namespaced kinds) to reject scope mismatches before they reach a worker.
+var clusterScopedKinds = map[schema.GroupKind]bool{
+	{Kind: "Namespace"}:        true,
+	{Kind: "Node"}:             true,
+	{Kind: "PersistentVolume"}: true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:                         true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:                  true,
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:                                   true,
+	{Group: "storage.k8s.io", Kind: "CSIDriver"}:                                      true,
+	{Group: "storage.k8s.io", Kind: "VolumeAttachment"}:                               true,
+	{Group: "networking.k8s.io", Kind: "IngressClass"}:                                true,
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"}:                               true,
+	{Group: "node.k8s.io", Kind: "RuntimeClass"}:                                      true,
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:                 true,
+	{Group: "apiregistration.k8s.io", Kind: "APIService"}:                             true,
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}:     true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}:   true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicy"}:        true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicyBinding"}: true,
+}
+
+// ValidateScope checks that namespace is set for well-known namespaced kinds and empty
+// for well-known cluster-scoped kinds. Unknown kinds such as CRDs are accepted and
+// checked against discovery by the reconciler at apply time.
+func ValidateScope(kind, apiVersion, namespace string) error {
+	gk := schema.GroupKind{Kind: kind}
+
+	if apiVersion != "" {
+		gv, err := schema.ParseGroupVersion(apiVersion)
+
+		if err != nil {
+			return fmt.Errorf("invalid api version %q: %w", apiVersion, err)
+		}
+
+		gk.Group = gv.Group
+	} else if gvr, ok := gvrMapping[kind]; ok {
+		gk.Group = gvr.Group
+	}
+
+	if clusterScopedKinds[gk] {
+		if namespace != "" {
+			return fmt.Errorf("%s is cluster-scoped and must not have a namespace", kind)
+		}
+
+		return nil
+	}
+
+	if gvr, ok := gvrMapping[kind]; ok && gvr.Group == gk.Group && namespace == "" {
+		return fmt.Errorf("%s is namespaced and requires a namespace", kind)
+	}
+
+	return nil
+}
+
+// IsNamespaced reports whether a REST mapping refers to a namespaced resource
+func IsNamespaced(mapping *meta.RESTMapping) bool {
+	return mapping.Scope.Name() == meta.RESTScopeNameNamespace
+}
+
+// ResourceClient returns the dynamic client for a mapping, scoped to namespace for
+// namespaced resources and cluster-wide for cluster-scoped resources. It returns an
+// error when namespace does not match the resource scope.
+func ResourceClient(client dynamic.Interface, mapping *meta.RESTMapping, namespace string) (dynamic.ResourceInterface, error) {
+	if !IsNamespaced(mapping) {
+		if namespace != "" {
+			return nil, fmt.Errorf("%s is cluster-scoped and must not have a namespace", mapping.GroupVersionKind.Kind)
+		}
+
+		return client.Resource(mapping.Resource), nil
+	}
+
+	if namespace == "" {
+		return nil, fmt.Errorf("%s is namespaced and requires a namespace", mapping.GroupVersionKind.Kind)
+	}
+
+	return client.Resource(mapping.Resource).Namespace(namespace), nil
+}
@@ -0,0 +1,188 @@
+package manager
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/models"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// adminTokenPrefix makes admin tokens recognisable in logs and secret scanners
+const adminTokenPrefix = "kadm_"
+
+// adminTokenLookupIDLength is the length of the hex lookup ID that follows adminTokenPrefix
+const adminTokenLookupIDLength = 16
+
+// AdminTokenManager provides operations for admin credentials used on the public API
+type AdminTokenManager struct {
+	DB *gorm.DB
+}
+
+// NewAdminTokenManager creates a new AdminTokenManager
+func NewAdminTokenManager(db *gorm.DB) *AdminTokenManager {
+	return &AdminTokenManager{DB: db}
+}
+
+// IsValidAdminScope reports whether scope is one of the known admin scopes
+func IsValidAdminScope(scope string) bool {
+	switch scope {
+	case models.AdminScopeReadOnly, models.AdminScopeWrite, models.AdminScopeAdmin:
+		return true
+	}
+
+	return false
+}
+
+// Create mints a new admin token and returns its plaintext value once
+func (m *AdminTokenManager) Create(ctx context.Context, req CreateAdminTokenRequest) (*AdminTokenWithSecret, error) {
+	if !IsValidAdminScope(req.Scope) {
+		return nil, ErrInvalidAdminScope
+	}
+
+	token, lookupID, err := generateAdminToken()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return m.createWithToken(ctx, req, token, &lookupID)
+}
+
+// EnsureBootstrap creates an admin-scoped token with the given plaintext value when no admin
+// tokens were ever created. Revoked tokens count, so revoking the bootstrap token sticks.
+func (m *AdminTokenManager) EnsureBootstrap(ctx context.Context, token string) error {
+	var count int64
+
+	err := m.DB.
+		WithContext(ctx).
+		Unscoped().
+		Model(&models.AdminToken{}).
+		Count(&count).
+		Error
+
+	if err != nil {
+		return fmt.Errorf("failed to count admin tokens: %w", err)
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	// An operator-chosen token is only looked up by ID when it happens to have the minted format
+	var lookupID *string
+
+	if id, ok := AdminTokenLookupID(token); ok {
+		lookupID = &id
+	}
+
+	_, err = m.createWithToken(ctx, CreateAdminTokenRequest{
+		Name:  "bootstrap",
+		Scope: models.AdminScopeAdmin,
+	}, token, lookupID)
+
+	return err
+}
+
+// List retrieves all non-revoked admin tokens
+func (m *AdminTokenManager) List(ctx context.Context) ([]models.AdminToken, error) {
+	var tokens []models.AdminToken
+
+	err := m.DB.
+		WithContext(ctx).
+		Order("created_at ASC").
+		Find(&tokens).
+		Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list admin tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// Revoke soft-deletes an admin token so it can no longer authenticate
+func (m *AdminTokenManager) Revoke(ctx context.Context, id uuid.UUID) error {
+	result := m.DB.
+		WithContext(ctx).
+		Delete(&models.AdminToken{}, id)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke admin token: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrAdminTokenNotFound
+	}
+
+	return nil
+}
+
+// AdminTokenLookupID extracts the lookup ID of a token minted by Create. Tokens without one,
+// such as the bootstrap token, report false.
+func AdminTokenLookupID(token string) (string, bool) {
+	rest, ok := strings.CutPrefix(token, adminTokenPrefix)
+
+	if !ok {
+		return "", false
+	}
+
+	lookupID, _, ok := strings.Cut(rest, "_")
+
+	if !ok || len(lookupID) != adminTokenLookupIDLength {
+		return "", false
+	}
+
+	return lookupID, true
+}
+
+func (m *AdminTokenManager) createWithToken(ctx context.Context, req CreateAdminTokenRequest, token string, lookupID *string) (*AdminTokenWithSecret, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(token), bcrypt.DefaultCost)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash admin token: %w", err)
+	}
+
+	adminToken := models.AdminToken{
+		ID:        uuid.Must(uuid.NewV7()),
+		Name:      req.Name,
+		TokenHash: string(hash),
+		LookupID:  lookupID,
+		Scope:     req.Scope,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	err = m.DB.
+		WithContext(ctx).
+		Create(&adminToken).
+		Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create admin token: %w", err)
+	}
+
+	return &AdminTokenWithSecret{
+		AdminToken: adminToken,
+		Token:      token,
+	}, nil
+}
+
+// generateAdminToken returns a token of the form kadm_<lookup id>_<secret> and its lookup ID
+func generateAdminToken() (string, string, error) {
+	buf := make([]byte, adminTokenLookupIDLength/2+32)
+
+	_, err := rand.Read(buf)
+
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate admin token: %w", err)
+	}
+
+	lookupID := hex.EncodeToString(buf[:adminTokenLookupIDLength/2])
+
+	return adminTokenPrefix + lookupID + "_" + hex.EncodeToString(buf[adminTokenLookupIDLength/2:]), lookupID, nil
+}
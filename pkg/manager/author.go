@@ -0,0 +1,36 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// authorKey is the context key for the author recorded in revision history
+type authorKey struct{}
+
+// WithAuthor returns a context whose changes are attributed to author in the revision history
+func WithAuthor(ctx context.Context, author string) context.Context {
+	return context.WithValue(ctx, authorKey{}, author)
+}
+
+// setAuthor makes the revision history triggers attribute changes in tx to the author in ctx.
+// The setting is transaction-local, so tx must be a transaction.
+func setAuthor(ctx context.Context, tx *gorm.DB) error {
+	author, _ := ctx.Value(authorKey{}).(string)
+
+	if author == "" {
+		return nil
+	}
+
+	err := tx.
+		Exec("SELECT set_config('kontrol.author', ?, true)", author).
+		Error
+
+	if err != nil {
+		return fmt.Errorf("failed to set author: %w", err)
+	}
+
+	return nil
+}
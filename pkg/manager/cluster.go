@@ -0,0 +1,281 @@
+package manager
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/models"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// clusterAPIKeyPrefix makes cluster API keys recognisable in logs and secret scanners
+const clusterAPIKeyPrefix = "sk_"
+
+// DefaultAPIKeyRotationOverlap is how long a rotated key keeps working when no overlap is given
+const DefaultAPIKeyRotationOverlap = time.Hour
+
+// ClusterManager provides operations for clusters and their worker API keys
+type ClusterManager struct {
+	DB *gorm.DB
+}
+
+// NewClusterManager creates a new ClusterManager
+func NewClusterManager(db *gorm.DB) *ClusterManager {
+	return &ClusterManager{DB: db}
+}
+
+// Create pre-provisions a cluster; creating an existing cluster is a no-op
+func (m *ClusterManager) Create(ctx context.Context, req CreateClusterRequest) (*models.Cluster, error) {
+	labels := req.Labels
+
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	cluster := models.Cluster{ID: req.ID, Labels: labels}
+
+	err := m.DB.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&cluster).
+		Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cluster: %w", err)
+	}
+
+	return m.Get(ctx, req.ID)
+}
+
+// Get retrieves a cluster by ID
+func (m *ClusterManager) Get(ctx context.Context, id string) (*models.Cluster, error) {
+	var cluster models.Cluster
+
+	err := m.DB.
+		WithContext(ctx).
+		Where("id = ?", id).
+		First(&cluster).
+		Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrClusterNotFound
+		}
+		return nil, fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	return &cluster, nil
+}
+
+// SetLabels replaces the labels of a cluster. Global resources whose cluster selector no
+// longer matches are removed from the cluster by its worker, newly matching ones are synced.
+func (m *ClusterManager) SetLabels(ctx context.Context, id string, labels map[string]string) (*models.Cluster, error) {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	data, err := json.Marshal(labels)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal cluster labels: %w", err)
+	}
+
+	tx := m.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	result := tx.
+		Model(&models.Cluster{ID: id}).
+		Update("labels", data)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to update cluster labels: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, ErrClusterNotFound
+	}
+
+	err = notifyChange(tx, "k_clusters", uuid.Nil, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit().Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return m.Get(ctx, id)
+}
+
+// List retrieves all clusters
+func (m *ClusterManager) List(ctx context.Context) ([]models.Cluster, error) {
+	var clusters []models.Cluster
+
+	err := m.DB.
+		WithContext(ctx).
+		Order("id ASC").
+		Find(&clusters).
+		Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	return clusters, nil
+}
+
+// CreateAPIKey mints a new API key for a cluster and returns its plaintext value once
+func (m *ClusterManager) CreateAPIKey(ctx context.Context, clusterID string, req CreateClusterAPIKeyRequest) (*ClusterAPIKeyWithSecret, error) {
+	_, err := m.Get(ctx, clusterID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return m.createAPIKey(m.DB.WithContext(ctx), clusterID, req.Name)
+}
+
+// ListAPIKeys retrieves the active API keys of a cluster, optionally filtered by name.
+// Revoked keys and keys past the end of their rotation overlap are left out.
+func (m *ClusterManager) ListAPIKeys(ctx context.Context, clusterID, name string) ([]models.ClusterAPIKey, error) {
+	var keys []models.ClusterAPIKey
+
+	query := m.DB.
+		WithContext(ctx).
+		Where("cluster_id = ? AND (expires_at IS NULL OR expires_at > ?)", clusterID, time.Now())
+
+	if name != "" {
+		query = query.Where("name = ?", name)
+	}
+
+	err := query.
+		Order("created_at ASC").
+		Find(&keys).
+		Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey soft-deletes a cluster API key so it can no longer authenticate
+func (m *ClusterManager) RevokeAPIKey(ctx context.Context, clusterID string, keyID uuid.UUID) error {
+	result := m.DB.
+		WithContext(ctx).
+		Where("id = ? AND cluster_id = ?", keyID, clusterID).
+		Delete(&models.ClusterAPIKey{})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke cluster api key: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrClusterAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// RotateAPIKey mints a replacement for a cluster API key and expires the old key after the overlap window
+func (m *ClusterManager) RotateAPIKey(ctx context.Context, clusterID string, keyID uuid.UUID, req RotateClusterAPIKeyRequest) (*ClusterAPIKeyWithSecret, error) {
+	overlap := DefaultAPIKeyRotationOverlap
+
+	if req.OverlapSeconds > 0 {
+		overlap = time.Duration(req.OverlapSeconds) * time.Second
+	}
+
+	tx := m.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	var oldKey models.ClusterAPIKey
+
+	err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND cluster_id = ?", keyID, clusterID).
+		First(&oldKey).
+		Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrClusterAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get cluster api key: %w", err)
+	}
+
+	expiresAt := time.Now().Add(overlap)
+
+	if oldKey.ExpiresAt == nil || oldKey.ExpiresAt.After(expiresAt) {
+		err = tx.
+			Model(&oldKey).
+			Update("expires_at", expiresAt).
+			Error
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to expire cluster api key: %w", err)
+		}
+	}
+
+	newKey, err := m.createAPIKey(tx, clusterID, oldKey.Name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit().Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return newKey, nil
+}
+
+func (m *ClusterManager) createAPIKey(db *gorm.DB, clusterID, name string) (*ClusterAPIKeyWithSecret, error) {
+	buf := make([]byte, 32)
+
+	_, err := rand.Read(buf)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate cluster api key: %w", err)
+	}
+
+	key := clusterAPIKeyPrefix + hex.EncodeToString(buf)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(key), bcrypt.DefaultCost)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash cluster api key: %w", err)
+	}
+
+	apiKey := models.ClusterAPIKey{
+		ID:        uuid.Must(uuid.NewV7()),
+		ClusterID: clusterID,
+		KeyHash:   string(hash),
+		Name:      name,
+	}
+
+	err = db.
+		Create(&apiKey).
+		Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cluster api key: %w", err)
+	}
+
+	return &ClusterAPIKeyWithSecret{
+		APIKey: apiKey,
+		Key:    key,
+	}, nil
+}
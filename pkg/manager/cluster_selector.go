@@ -0,0 +1,109 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/database"
+	"github.com/targc/kontrol/pkg/models"
+	"gorm.io/gorm"
+)
+
+// validateClusterSelector checks that every expression uses a known operator and has
+// values only for In and NotIn
+func validateClusterSelector(selector *models.ClusterSelector) error {
+	if selector == nil {
+		return nil
+	}
+
+	for _, expr := range selector.MatchExpressions {
+		if expr.Key == "" {
+			return fmt.Errorf("%w: cluster selector expression without key", ErrInvalidResource)
+		}
+
+		switch expr.Operator {
+		case models.SelectorOpIn, models.SelectorOpNotIn:
+			if len(expr.Values) == 0 {
+				return fmt.Errorf("%w: cluster selector operator %s on %q requires values", ErrInvalidResource, expr.Operator, expr.Key)
+			}
+		case models.SelectorOpExists, models.SelectorOpDoesNotExist:
+			if len(expr.Values) != 0 {
+				return fmt.Errorf("%w: cluster selector operator %s on %q does not take values", ErrInvalidResource, expr.Operator, expr.Key)
+			}
+		default:
+			return fmt.Errorf("%w: unknown cluster selector operator %q", ErrInvalidResource, expr.Operator)
+		}
+	}
+
+	return nil
+}
+
+// ClusterSelectorMatches reports whether a cluster with the given labels is selected.
+// It mirrors the kontrol_selector_matches SQL function; a nil selector matches every cluster.
+func ClusterSelectorMatches(selector *models.ClusterSelector, labels map[string]string) bool {
+	if selector == nil {
+		return true
+	}
+
+	for key, value := range selector.MatchLabels {
+		if labelValue, ok := labels[key]; !ok || labelValue != value {
+			return false
+		}
+	}
+
+	for _, expr := range selector.MatchExpressions {
+		labelValue, exists := labels[expr.Key]
+
+		switch expr.Operator {
+		case models.SelectorOpIn:
+			if !exists || !slices.Contains(expr.Values, labelValue) {
+				return false
+			}
+		case models.SelectorOpNotIn:
+			if exists && slices.Contains(expr.Values, labelValue) {
+				return false
+			}
+		case models.SelectorOpExists:
+			if !exists {
+				return false
+			}
+		case models.SelectorOpDoesNotExist:
+			if exists {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// selectorJSON encodes a cluster selector for raw SQL; a nil selector becomes NULL
+func selectorJSON(selector *models.ClusterSelector) []byte {
+	if selector == nil {
+		return nil
+	}
+
+	data, _ := json.Marshal(selector)
+
+	return data
+}
+
+// notifyChange publishes a change event like the generation triggers do, for changes
+// that decide which clusters see a global resource without bumping any generation.
+// Like the triggers' notifications, it is only delivered once tx commits.
+func notifyChange(tx *gorm.DB, table string, id uuid.UUID, clusterID string) error {
+	err := tx.
+		Exec("SELECT pg_notify(?, json_build_object('table', ?::text, 'id', ?::uuid, 'cluster_id', ?::text)::text)",
+			database.ChangesChannel, table, id, clusterID).
+		Error
+
+	if err != nil {
+		return fmt.Errorf("failed to notify change: %w", err)
+	}
+
+	return nil
+}
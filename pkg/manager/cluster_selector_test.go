@@ -0,0 +1,158 @@
+package manager
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/targc/kontrol/pkg/database"
+	"github.com/targc/kontrol/pkg/models"
+)
+
+var clusterSelectorTests = []struct {
+	name     string
+	selector *models.ClusterSelector
+	labels   map[string]string
+	want     bool
+}{
+	{
+		name:   "nil selector matches every cluster",
+		labels: map[string]string{"env": "prod"},
+		want:   true,
+	},
+	{
+		name:     "empty selector matches every cluster",
+		selector: &models.ClusterSelector{},
+		want:     true,
+	},
+	{
+		name:     "match labels",
+		selector: &models.ClusterSelector{MatchLabels: map[string]string{"env": "prod"}},
+		labels:   map[string]string{"env": "prod", "region": "eu"},
+		want:     true,
+	},
+	{
+		name:     "match labels with different value",
+		selector: &models.ClusterSelector{MatchLabels: map[string]string{"env": "prod"}},
+		labels:   map[string]string{"env": "staging"},
+	},
+	{
+		name:     "match labels without labels",
+		selector: &models.ClusterSelector{MatchLabels: map[string]string{"env": "prod"}},
+	},
+	{
+		name: "in",
+		selector: &models.ClusterSelector{MatchExpressions: []models.ClusterSelectorRequirement{
+			{Key: "region", Operator: models.SelectorOpIn, Values: []string{"eu", "us"}},
+		}},
+		labels: map[string]string{"region": "us"},
+		want:   true,
+	},
+	{
+		name: "in without label",
+		selector: &models.ClusterSelector{MatchExpressions: []models.ClusterSelectorRequirement{
+			{Key: "region", Operator: models.SelectorOpIn, Values: []string{"eu"}},
+		}},
+	},
+	{
+		name: "not in",
+		selector: &models.ClusterSelector{MatchExpressions: []models.ClusterSelectorRequirement{
+			{Key: "region", Operator: models.SelectorOpNotIn, Values: []string{"eu"}},
+		}},
+		labels: map[string]string{"region": "eu"},
+	},
+	{
+		name: "not in without label",
+		selector: &models.ClusterSelector{MatchExpressions: []models.ClusterSelectorRequirement{
+			{Key: "region", Operator: models.SelectorOpNotIn, Values: []string{"eu"}},
+		}},
+		want: true,
+	},
+	{
+		name: "exists",
+		selector: &models.ClusterSelector{MatchExpressions: []models.ClusterSelectorRequirement{
+			{Key: "gpu", Operator: models.SelectorOpExists},
+		}},
+		labels: map[string]string{"gpu": ""},
+		want:   true,
+	},
+	{
+		name: "does not exist",
+		selector: &models.ClusterSelector{MatchExpressions: []models.ClusterSelectorRequirement{
+			{Key: "gpu", Operator: models.SelectorOpDoesNotExist},
+		}},
+		labels: map[string]string{"gpu": "a100"},
+	},
+	{
+		name: "labels and expressions must all match",
+		selector: &models.ClusterSelector{
+			MatchLabels: map[string]string{"env": "prod"},
+			MatchExpressions: []models.ClusterSelectorRequirement{
+				{Key: "region", Operator: models.SelectorOpIn, Values: []string{"eu"}},
+				{Key: "legacy", Operator: models.SelectorOpDoesNotExist},
+			},
+		},
+		labels: map[string]string{"env": "prod", "region": "eu", "legacy": "true"},
+	},
+	{
+		name: "unknown operator never matches",
+		selector: &models.ClusterSelector{MatchExpressions: []models.ClusterSelectorRequirement{
+			{Key: "env", Operator: "Gt", Values: []string{"1"}},
+		}},
+		labels: map[string]string{"env": "2"},
+	},
+}
+
+func TestClusterSelectorMatches(t *testing.T) {
+	for _, tt := range clusterSelectorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClusterSelectorMatches(tt.selector, tt.labels); got != tt.want {
+				t.Errorf("ClusterSelectorMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// The SQL function must select the same clusters, since the API filters with it while
+// rollout waves and overrides are matched in Go. Set KONTROL_TEST_DB_URL to a scratch
+// Postgres database to run it.
+func TestClusterSelectorMatchesSQLParity(t *testing.T) {
+	dbURL := os.Getenv("KONTROL_TEST_DB_URL")
+
+	if dbURL == "" {
+		t.Skip("KONTROL_TEST_DB_URL not set")
+	}
+
+	db, err := database.Connect(dbURL)
+
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	err = database.AutoMigrate(db)
+
+	if err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	for _, tt := range clusterSelectorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels, _ := json.Marshal(tt.labels)
+
+			var got bool
+
+			err := db.
+				Raw("SELECT kontrol_selector_matches(?::jsonb, ?::jsonb)", selectorJSON(tt.selector), labels).
+				Scan(&got).
+				Error
+
+			if err != nil {
+				t.Fatalf("failed to evaluate selector: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("kontrol_selector_matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package manager
+
+import "errors"
+
+var (
+	// ErrResourceNotFound is returned when a resource does not exist or has been deleted
+	ErrResourceNotFound = errors.New("resource not found")
+
+	// ErrGlobalResourceNotFound is returned when a global resource does not exist or has been deleted
+	ErrGlobalResourceNotFound = errors.New("global resource not found")
+
+	// ErrRevisionNotFound is returned when a resource or global resource has no history entry for a revision
+	ErrRevisionNotFound = errors.New("revision not found")
+
+	// ErrResourceConflict is returned when a global resource would take over a resource with the
+	// same key that is not derived from it
+	ErrResourceConflict = errors.New("resource conflict")
+
+	// ErrInvalidResource is returned when a resource fails validation, e.g. a namespace/scope mismatch
+	ErrInvalidResource = errors.New("invalid resource")
+
+	// ErrClusterNotFound is returned when a cluster has not been provisioned or registered
+	ErrClusterNotFound = errors.New("cluster not found")
+
+	// ErrClusterAPIKeyNotFound is returned when a cluster API key does not exist or has been revoked
+	ErrClusterAPIKeyNotFound = errors.New("cluster api key not found")
+
+	// ErrAdminTokenNotFound is returned when an admin token does not exist or has been revoked
+	ErrAdminTokenNotFound = errors.New("admin token not found")
+
+	// ErrInvalidAdminScope is returned when an admin token scope is not one of the known scopes
+	ErrInvalidAdminScope = errors.New("invalid admin scope")
+)
@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/k8s"
 	"github.com/targc/kontrol/pkg/models"
 	"gorm.io/gorm"
 )
@@ -22,9 +24,39 @@ func NewGlobalResourceManager(db *gorm.DB) *GlobalResourceManager {
 
 // Create creates a new global resource
 func (m *GlobalResourceManager) Create(ctx context.Context, req CreateGlobalResourceRequest) (*GlobalResourceWithSyncStatus, error) {
+	err := k8s.ValidateScope(req.Kind, req.APIVersion, req.Namespace)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResource, err)
+	}
+
+	err = validateClusterSelector(req.ClusterSelector)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateOverrides(req.DesiredSpec, req.Overrides)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateRolloutStrategy(req.RolloutStrategy)
+
+	if err != nil {
+		return nil, err
+	}
+
 	tx := m.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
+	err = setAuthor(ctx, tx)
+
+	if err != nil {
+		return nil, err
+	}
+
 	globalResource := models.GlobalResource{
 		ID:          uuid.Must(uuid.NewV7()),
 		Namespace:   req.Namespace,
@@ -34,9 +66,14 @@ func (m *GlobalResourceManager) Create(ctx context.Context, req CreateGlobalReso
 		DesiredSpec: req.DesiredSpec,
 		Generation:  1,
 		Revision:    1,
+		SelfHeal:    req.SelfHeal != nil && *req.SelfHeal,
+
+		ClusterSelector: req.ClusterSelector,
+		Overrides:       req.Overrides,
+		RolloutStrategy: req.RolloutStrategy,
 	}
 
-	err := tx.
+	err = tx.
 		Create(&globalResource).
 		Error
 
@@ -64,7 +101,7 @@ func (m *GlobalResourceManager) Get(ctx context.Context, id uuid.UUID) (*GlobalR
 
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("global resource not found")
+			return nil, ErrGlobalResourceNotFound
 		}
 		return nil, fmt.Errorf("failed to get global resource: %w", err)
 	}
@@ -83,6 +120,9 @@ func (m *GlobalResourceManager) GetByKindAndName(ctx context.Context, namespace,
 		Error
 
 	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrGlobalResourceNotFound
+		}
 		return nil, fmt.Errorf("failed to get global resource: %w", err)
 	}
 
@@ -91,6 +131,30 @@ func (m *GlobalResourceManager) GetByKindAndName(ctx context.Context, namespace,
 
 // Upsert creates or updates a global resource atomically using INSERT ON CONFLICT
 func (m *GlobalResourceManager) Upsert(ctx context.Context, req CreateGlobalResourceRequest) (*GlobalResourceWithSyncStatus, error) {
+	err := k8s.ValidateScope(req.Kind, req.APIVersion, req.Namespace)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResource, err)
+	}
+
+	err = validateClusterSelector(req.ClusterSelector)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateOverrides(req.DesiredSpec, req.Overrides)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateRolloutStrategy(req.RolloutStrategy)
+
+	if err != nil {
+		return nil, err
+	}
+
 	globalResource := models.GlobalResource{
 		ID:          uuid.Must(uuid.NewV7()),
 		Namespace:   req.Namespace,
@@ -100,27 +164,48 @@ func (m *GlobalResourceManager) Upsert(ctx context.Context, req CreateGlobalReso
 		DesiredSpec: req.DesiredSpec,
 		Generation:  1,
 		Revision:    1,
+		SelfHeal:    req.SelfHeal != nil && *req.SelfHeal,
 	}
 
-	err := m.DB.
-		WithContext(ctx).
+	tx := m.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	err = setAuthor(ctx, tx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.
 		Exec(`
-			INSERT INTO k_global_resources (id, namespace, kind, name, api_version, desired_spec, generation, revision, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())
+			INSERT INTO k_global_resources (id, namespace, kind, name, api_version, desired_spec, generation, revision, self_heal, cluster_selector, overrides, rollout_strategy, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())
 			ON CONFLICT (namespace, kind, name) WHERE deleted_at IS NULL
 			DO UPDATE SET
 				api_version = EXCLUDED.api_version,
 				desired_spec = EXCLUDED.desired_spec,
 				revision = k_global_resources.revision + 1,
+				self_heal = COALESCE(?::boolean, k_global_resources.self_heal),
+				cluster_selector = EXCLUDED.cluster_selector,
+				overrides = EXCLUDED.overrides,
+				rollout_strategy = EXCLUDED.rollout_strategy,
 				updated_at = NOW()
 		`, globalResource.ID, globalResource.Namespace, globalResource.Kind, globalResource.Name,
-			globalResource.APIVersion, globalResource.DesiredSpec, globalResource.Generation, globalResource.Revision).
+			globalResource.APIVersion, globalResource.DesiredSpec, globalResource.Generation, globalResource.Revision, globalResource.SelfHeal,
+			selectorJSON(req.ClusterSelector), overridesJSON(req.Overrides), rolloutStrategyJSON(req.RolloutStrategy),
+			req.SelfHeal).
 		Error
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to upsert global resource: %w", err)
 	}
 
+	err = tx.Commit().Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return m.GetByKindAndName(ctx, req.Namespace, req.Kind, req.Name)
 }
 
@@ -156,15 +241,21 @@ func (m *GlobalResourceManager) Update(ctx context.Context, id uuid.UUID, desire
 	tx := m.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
+	err := setAuthor(ctx, tx)
+
+	if err != nil {
+		return nil, err
+	}
+
 	var globalResource models.GlobalResource
 
-	err := tx.
+	err = tx.
 		First(&globalResource, id).
 		Error
 
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("global resource not found")
+			return nil, ErrGlobalResourceNotFound
 		}
 		return nil, fmt.Errorf("failed to get global resource: %w", err)
 	}
@@ -197,6 +288,214 @@ func (m *GlobalResourceManager) Update(ctx context.Context, id uuid.UUID, desire
 	return m.Get(ctx, id)
 }
 
+// SetSelfHeal turns self-heal on or off for a global resource and the resources derived
+// from it (does not change generation).
+// Enabling it on missing derived resources requests a heal so their objects are re-created.
+func (m *GlobalResourceManager) SetSelfHeal(ctx context.Context, id uuid.UUID, enabled bool) (*GlobalResourceWithSyncStatus, error) {
+	tx := m.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	var globalResource models.GlobalResource
+
+	err := tx.
+		First(&globalResource, id).
+		Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrGlobalResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get global resource: %w", err)
+	}
+
+	err = tx.
+		Model(&globalResource).
+		Update("self_heal", enabled).
+		Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update global resource: %w", err)
+	}
+
+	derived := tx.
+		Model(&models.Resource{}).
+		Select("id").
+		Where("global_resource_id = ?", globalResource.ID)
+
+	err = tx.
+		Model(&models.Resource{}).
+		Where("id IN (?)", derived).
+		Update("self_heal", enabled).
+		Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update derived resources: %w", err)
+	}
+
+	if enabled {
+		err = tx.
+			Model(&models.ResourceAppliedState{}).
+			Where("resource_id IN (?) AND missing_since IS NOT NULL AND heal_requested_at IS NULL", derived).
+			Update("heal_requested_at", time.Now()).
+			Error
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to request heal: %w", err)
+		}
+	}
+
+	err = tx.Commit().Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return m.Get(ctx, id)
+}
+
+// SetClusterSelector changes which clusters a global resource targets (does not change
+// generation). Clusters that stop matching have the derived resource removed by their
+// worker; newly matching clusters sync it.
+func (m *GlobalResourceManager) SetClusterSelector(ctx context.Context, id uuid.UUID, selector *models.ClusterSelector) (*GlobalResourceWithSyncStatus, error) {
+	err := validateClusterSelector(selector)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tx := m.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	var globalResource models.GlobalResource
+
+	err = tx.
+		First(&globalResource, id).
+		Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrGlobalResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get global resource: %w", err)
+	}
+
+	err = tx.
+		Model(&globalResource).
+		Update("cluster_selector", selectorJSON(selector)).
+		Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update global resource: %w", err)
+	}
+
+	err = notifyChange(tx, "k_global_resources", id, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit().Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return m.Get(ctx, id)
+}
+
+// SetOverrides replaces the per-cluster overrides of a global resource. Generation
+// auto-increments via DB trigger, so every targeted cluster re-derives its resource.
+func (m *GlobalResourceManager) SetOverrides(ctx context.Context, id uuid.UUID, overrides []models.ClusterOverride) (*GlobalResourceWithSyncStatus, error) {
+	tx := m.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	var globalResource models.GlobalResource
+
+	err := tx.
+		First(&globalResource, id).
+		Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrGlobalResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get global resource: %w", err)
+	}
+
+	err = validateOverrides(globalResource.DesiredSpec, overrides)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.
+		Model(&globalResource).
+		Update("overrides", overridesJSON(overrides)).
+		Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update global resource: %w", err)
+	}
+
+	err = tx.Commit().Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return m.Get(ctx, id)
+}
+
+// SetRolloutStrategy changes how new generations of a global resource roll out across
+// clusters (does not change generation). It also pauses and resumes a rollout; clusters
+// admitted by the new strategy are woken right away.
+func (m *GlobalResourceManager) SetRolloutStrategy(ctx context.Context, id uuid.UUID, strategy *models.RolloutStrategy) (*GlobalResourceWithSyncStatus, error) {
+	err := validateRolloutStrategy(strategy)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tx := m.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	var globalResource models.GlobalResource
+
+	err = tx.
+		First(&globalResource, id).
+		Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrGlobalResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get global resource: %w", err)
+	}
+
+	err = tx.
+		Model(&globalResource).
+		Update("rollout_strategy", rolloutStrategyJSON(strategy)).
+		Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update global resource: %w", err)
+	}
+
+	err = notifyChange(tx, "k_global_resources", id, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit().Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return m.Get(ctx, id)
+}
+
 // Delete soft-deletes a global resource (generation auto-increments via DB trigger)
 func (m *GlobalResourceManager) Delete(ctx context.Context, id uuid.UUID) error {
 	tx := m.DB.WithContext(ctx).Begin()
@@ -210,7 +509,7 @@ func (m *GlobalResourceManager) Delete(ctx context.Context, id uuid.UUID) error
 
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return fmt.Errorf("global resource not found")
+			return ErrGlobalResourceNotFound
 		}
 		return fmt.Errorf("failed to get global resource: %w", err)
 	}
@@ -232,13 +531,82 @@ func (m *GlobalResourceManager) Delete(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
+// ListRevisions retrieves the revision history of a global resource, newest first
+func (m *GlobalResourceManager) ListRevisions(ctx context.Context, id uuid.UUID) ([]models.GlobalResourceRevision, error) {
+	err := m.DB.
+		WithContext(ctx).
+		Select("id").
+		First(&models.GlobalResource{}, id).
+		Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrGlobalResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get global resource: %w", err)
+	}
+
+	var revisions []models.GlobalResourceRevision
+
+	err = m.DB.
+		WithContext(ctx).
+		Where("global_resource_id = ?", id).
+		Order("generation DESC").
+		Find(&revisions).
+		Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list global resource revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// Rollback restores the desired spec of a historical revision as a new generation.
+// The revision number goes back to the restored one; if it was used more than once,
+// the most recent entry is restored.
+func (m *GlobalResourceManager) Rollback(ctx context.Context, id uuid.UUID, revision int) (*GlobalResourceWithSyncStatus, error) {
+	err := m.DB.
+		WithContext(ctx).
+		Select("id").
+		First(&models.GlobalResource{}, id).
+		Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrGlobalResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get global resource: %w", err)
+	}
+
+	var entry models.GlobalResourceRevision
+
+	err = m.DB.
+		WithContext(ctx).
+		Where("global_resource_id = ? AND revision = ?", id, revision).
+		Order("generation DESC").
+		First(&entry).
+		Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, fmt.Errorf("failed to get global resource revision: %w", err)
+	}
+
+	return m.Update(ctx, id, entry.DesiredSpec, &entry.Revision)
+}
+
 // buildGlobalResourceWithSyncStatus builds a GlobalResourceWithSyncStatus from a GlobalResource
 func (m *GlobalResourceManager) buildGlobalResourceWithSyncStatus(ctx context.Context, gr *models.GlobalResource) (*GlobalResourceWithSyncStatus, error) {
 	var totalClusters int64
 
+	// Only clusters matched by the cluster selector are expected to sync
 	err := m.DB.
 		WithContext(ctx).
 		Model(&models.Cluster{}).
+		Where("kontrol_selector_matches(?::jsonb, labels)", selectorJSON(gr.ClusterSelector)).
 		Count(&totalClusters).
 		Error
 
@@ -267,14 +635,22 @@ func (m *GlobalResourceManager) buildGlobalResourceWithSyncStatus(ctx context.Co
 			ClusterID:        state.ClusterID,
 			SyncedGeneration: state.SyncedGeneration,
 			IsSynced:         isSynced,
+			Conflict:         state.Conflict,
 		}
 	}
 
+	rollout, err := m.rolloutStatus(ctx, gr)
+
+	if err != nil {
+		return nil, err
+	}
+
 	return &GlobalResourceWithSyncStatus{
 		GlobalResource:  *gr,
 		TotalClusters:   int(totalClusters),
 		SyncedClusters:  syncedCount,
 		ClusterStatuses: clusterStatuses,
+		Rollout:         rollout,
 	}, nil
 }
 
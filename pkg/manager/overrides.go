@@ -0,0 +1,80 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/targc/kontrol/pkg/models"
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+)
+
+// ApplyOverrides returns the desired spec derived for a cluster: the base spec with every
+// override matching the cluster merged in, in order
+func ApplyOverrides(desiredSpec []byte, overrides []models.ClusterOverride, cluster *models.Cluster) ([]byte, error) {
+	spec := desiredSpec
+
+	for i, override := range overrides {
+		if !overrideMatches(&override, cluster) {
+			continue
+		}
+
+		patched, err := jsonpatch.MergePatch(spec, override.Patch)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply override %d: %w", i, err)
+		}
+
+		spec = patched
+	}
+
+	return spec, nil
+}
+
+func overrideMatches(override *models.ClusterOverride, cluster *models.Cluster) bool {
+	if override.ClusterID != "" {
+		return override.ClusterID == cluster.ID
+	}
+
+	return ClusterSelectorMatches(override.ClusterSelector, cluster.Labels)
+}
+
+// validateOverrides checks that every override targets either a cluster or a selector and
+// that its patch is a JSON object that merges cleanly into desiredSpec
+func validateOverrides(desiredSpec []byte, overrides []models.ClusterOverride) error {
+	for i, override := range overrides {
+		if (override.ClusterID == "") == (override.ClusterSelector == nil) {
+			return fmt.Errorf("%w: override %d must set exactly one of cluster_id and cluster_selector", ErrInvalidResource, i)
+		}
+
+		err := validateClusterSelector(override.ClusterSelector)
+
+		if err != nil {
+			return err
+		}
+
+		var patch map[string]interface{}
+
+		if err := json.Unmarshal(override.Patch, &patch); err != nil || patch == nil {
+			return fmt.Errorf("%w: override %d patch must be a JSON object", ErrInvalidResource, i)
+		}
+
+		_, err = jsonpatch.MergePatch(desiredSpec, override.Patch)
+
+		if err != nil {
+			return fmt.Errorf("%w: override %d: %v", ErrInvalidResource, i, err)
+		}
+	}
+
+	return nil
+}
+
+// overridesJSON encodes overrides for raw SQL; no overrides become NULL
+func overridesJSON(overrides []models.ClusterOverride) []byte {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	data, _ := json.Marshal(overrides)
+
+	return data
+}
@@ -0,0 +1,112 @@
+package manager
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/targc/kontrol/pkg/models"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	base := `{"spec":{"replicas":2,"image":"registry.example.com/app:1","env":{"LOG":"info"}}}`
+
+	cluster := &models.Cluster{
+		ID:     "prod-eu",
+		Labels: map[string]string{"env": "prod", "region": "eu"},
+	}
+
+	tests := []struct {
+		name      string
+		overrides []models.ClusterOverride
+		want      string
+	}{
+		{
+			name: "no overrides",
+			want: base,
+		},
+		{
+			name: "override by cluster id",
+			overrides: []models.ClusterOverride{
+				{ClusterID: "prod-eu", Patch: json.RawMessage(`{"spec":{"replicas":5}}`)},
+			},
+			want: `{"spec":{"replicas":5,"image":"registry.example.com/app:1","env":{"LOG":"info"}}}`,
+		},
+		{
+			name: "override for another cluster",
+			overrides: []models.ClusterOverride{
+				{ClusterID: "prod-us", Patch: json.RawMessage(`{"spec":{"replicas":5}}`)},
+			},
+			want: base,
+		},
+		{
+			name: "override by selector",
+			overrides: []models.ClusterOverride{
+				{
+					ClusterSelector: &models.ClusterSelector{MatchLabels: map[string]string{"region": "eu"}},
+					Patch:           json.RawMessage(`{"spec":{"image":"eu.registry.example.com/app:1"}}`),
+				},
+			},
+			want: `{"spec":{"replicas":2,"image":"eu.registry.example.com/app:1","env":{"LOG":"info"}}}`,
+		},
+		{
+			name: "selector not matching",
+			overrides: []models.ClusterOverride{
+				{
+					ClusterSelector: &models.ClusterSelector{MatchLabels: map[string]string{"env": "staging"}},
+					Patch:           json.RawMessage(`{"spec":{"replicas":1}}`),
+				},
+			},
+			want: base,
+		},
+		{
+			name: "later overrides win",
+			overrides: []models.ClusterOverride{
+				{
+					ClusterSelector: &models.ClusterSelector{MatchLabels: map[string]string{"env": "prod"}},
+					Patch:           json.RawMessage(`{"spec":{"replicas":3,"env":{"LOG":"warn"}}}`),
+				},
+				{ClusterID: "prod-eu", Patch: json.RawMessage(`{"spec":{"replicas":4}}`)},
+			},
+			want: `{"spec":{"replicas":4,"image":"registry.example.com/app:1","env":{"LOG":"warn"}}}`,
+		},
+		{
+			name: "null removes a field",
+			overrides: []models.ClusterOverride{
+				{ClusterID: "prod-eu", Patch: json.RawMessage(`{"spec":{"env":null}}`)},
+			},
+			want: `{"spec":{"replicas":2,"image":"registry.example.com/app:1"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyOverrides([]byte(base), tt.overrides, cluster)
+
+			if err != nil {
+				t.Fatalf("ApplyOverrides() error = %v", err)
+			}
+
+			var gotValue, wantValue interface{}
+
+			json.Unmarshal(got, &gotValue)
+			json.Unmarshal([]byte(tt.want), &wantValue)
+
+			if !reflect.DeepEqual(gotValue, wantValue) {
+				t.Errorf("ApplyOverrides() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyOverridesInvalidPatch(t *testing.T) {
+	overrides := []models.ClusterOverride{
+		{ClusterID: "prod-eu", Patch: json.RawMessage(`{`)},
+	}
+
+	_, err := ApplyOverrides([]byte(`{"spec":{}}`), overrides, &models.Cluster{ID: "prod-eu"})
+
+	if err == nil {
+		t.Error("ApplyOverrides() expected error for invalid patch")
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/k8s"
 	"github.com/targc/kontrol/pkg/models"
 	"gorm.io/gorm"
 )
@@ -22,9 +24,21 @@ func NewResourceManager(db *gorm.DB) *ResourceManager {
 
 // Create creates a new resource atomically
 func (m *ResourceManager) Create(ctx context.Context, req CreateResourceRequest) (*ResourceWithState, error) {
+	err := k8s.ValidateScope(req.Kind, req.APIVersion, req.Namespace)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResource, err)
+	}
+
 	tx := m.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
+	err = setAuthor(ctx, tx)
+
+	if err != nil {
+		return nil, err
+	}
+
 	resource := models.Resource{
 		ID:          uuid.Must(uuid.NewV7()),
 		ClusterID:   req.ClusterID,
@@ -35,9 +49,10 @@ func (m *ResourceManager) Create(ctx context.Context, req CreateResourceRequest)
 		DesiredSpec: req.DesiredSpec,
 		Generation:  1,
 		Revision:    1,
+		SelfHeal:    req.SelfHeal != nil && *req.SelfHeal,
 	}
 
-	err := tx.
+	err = tx.
 		Create(&resource).
 		Error
 
@@ -65,38 +80,12 @@ func (m *ResourceManager) Get(ctx context.Context, id uuid.UUID) (*ResourceWithS
 
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("resource not found")
+			return nil, ErrResourceNotFound
 		}
 		return nil, fmt.Errorf("failed to get resource: %w", err)
 	}
 
-	var appliedState models.ResourceAppliedState
-
-	m.DB.
-		WithContext(ctx).
-		Where("resource_id = ?", resource.ID).
-		First(&appliedState)
-
-	var currentState models.ResourceCurrentState
-
-	m.DB.
-		WithContext(ctx).
-		Where("resource_id = ?", resource.ID).
-		First(&currentState)
-
-	result := &ResourceWithState{
-		Resource: resource,
-	}
-
-	if appliedState.ID != uuid.Nil {
-		result.AppliedState = &appliedState
-	}
-
-	if currentState.ID != uuid.Nil {
-		result.CurrentState = &currentState
-	}
-
-	return result, nil
+	return m.buildResourceWithState(ctx, &resource), nil
 }
 
 // List retrieves all resources for a cluster with their states
@@ -121,31 +110,38 @@ func (m *ResourceManager) List(ctx context.Context, clusterID string) ([]*Resour
 
 	result := make([]*ResourceWithState, len(resources))
 	for i, r := range resources {
-		var appliedState models.ResourceAppliedState
+		result[i] = m.buildResourceWithState(ctx, &r)
+	}
 
-		m.DB.
-			WithContext(ctx).
-			Where("resource_id = ?", r.ID).
-			First(&appliedState)
+	return result, nil
+}
 
-		var currentState models.ResourceCurrentState
+// ListDrifted retrieves resources whose live object no longer matches the desired spec,
+// optionally filtered by cluster
+func (m *ResourceManager) ListDrifted(ctx context.Context, clusterID string) ([]*ResourceWithState, error) {
+	var resources []models.Resource
 
-		m.DB.
-			WithContext(ctx).
-			Where("resource_id = ?", r.ID).
-			First(&currentState)
+	query := m.DB.
+		WithContext(ctx).
+		Model(&models.Resource{}).
+		Joins("JOIN k_resource_current_states cs ON cs.resource_id = k_resources.id AND cs.deleted_at IS NULL").
+		Where("cs.drifted AND cs.generation = k_resources.generation")
 
-		result[i] = &ResourceWithState{
-			Resource: r,
-		}
+	if clusterID != "" {
+		query = query.Where("k_resources.cluster_id = ?", clusterID)
+	}
 
-		if appliedState.ID != uuid.Nil {
-			result[i].AppliedState = &appliedState
-		}
+	err := query.
+		Find(&resources).
+		Error
 
-		if currentState.ID != uuid.Nil {
-			result[i].CurrentState = &currentState
-		}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list drifted resources: %w", err)
+	}
+
+	result := make([]*ResourceWithState, len(resources))
+	for i, r := range resources {
+		result[i] = m.buildResourceWithState(ctx, &r)
 	}
 
 	return result, nil
@@ -156,15 +152,21 @@ func (m *ResourceManager) Update(ctx context.Context, id uuid.UUID, desiredSpec
 	tx := m.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
+	err := setAuthor(ctx, tx)
+
+	if err != nil {
+		return nil, err
+	}
+
 	var resource models.Resource
 
-	err := tx.
+	err = tx.
 		First(&resource, id).
 		Error
 
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("resource not found")
+			return nil, ErrResourceNotFound
 		}
 		return nil, fmt.Errorf("failed to get resource: %w", err)
 	}
@@ -197,6 +199,46 @@ func (m *ResourceManager) Update(ctx context.Context, id uuid.UUID, desiredSpec
 	return m.Get(ctx, id)
 }
 
+// SetSelfHeal turns self-heal on or off for a resource (does not change generation).
+// Enabling it on a missing resource requests a heal so the object is re-created.
+func (m *ResourceManager) SetSelfHeal(ctx context.Context, id uuid.UUID, enabled bool) (*ResourceWithState, error) {
+	tx := m.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	result := tx.
+		Model(&models.Resource{}).
+		Where("id = ?", id).
+		Update("self_heal", enabled)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to update resource: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, ErrResourceNotFound
+	}
+
+	if enabled {
+		err := tx.
+			Model(&models.ResourceAppliedState{}).
+			Where("resource_id = ? AND missing_since IS NOT NULL AND heal_requested_at IS NULL", id).
+			Update("heal_requested_at", time.Now()).
+			Error
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to request heal: %w", err)
+		}
+	}
+
+	err := tx.Commit().Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return m.Get(ctx, id)
+}
+
 // Delete soft-deletes a resource atomically (generation auto-increments via DB trigger)
 func (m *ResourceManager) Delete(ctx context.Context, id uuid.UUID) error {
 	tx := m.DB.WithContext(ctx).Begin()
@@ -210,7 +252,7 @@ func (m *ResourceManager) Delete(ctx context.Context, id uuid.UUID) error {
 
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return fmt.Errorf("resource not found")
+			return ErrResourceNotFound
 		}
 		return fmt.Errorf("failed to get resource: %w", err)
 	}
@@ -232,6 +274,120 @@ func (m *ResourceManager) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// ListRevisions retrieves the revision history of a resource, newest first
+func (m *ResourceManager) ListRevisions(ctx context.Context, id uuid.UUID) ([]models.ResourceRevision, error) {
+	err := m.DB.
+		WithContext(ctx).
+		Select("id").
+		First(&models.Resource{}, id).
+		Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get resource: %w", err)
+	}
+
+	var revisions []models.ResourceRevision
+
+	err = m.DB.
+		WithContext(ctx).
+		Where("resource_id = ?", id).
+		Order("generation DESC").
+		Find(&revisions).
+		Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// Rollback restores the desired spec of a historical revision as a new generation.
+// The revision number goes back to the restored one; if it was used more than once,
+// the most recent entry is restored.
+func (m *ResourceManager) Rollback(ctx context.Context, id uuid.UUID, revision int) (*ResourceWithState, error) {
+	err := m.DB.
+		WithContext(ctx).
+		Select("id").
+		First(&models.Resource{}, id).
+		Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get resource: %w", err)
+	}
+
+	var entry models.ResourceRevision
+
+	err = m.DB.
+		WithContext(ctx).
+		Where("resource_id = ? AND revision = ?", id, revision).
+		Order("generation DESC").
+		First(&entry).
+		Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, fmt.Errorf("failed to get resource revision: %w", err)
+	}
+
+	return m.Update(ctx, id, entry.DesiredSpec, &entry.Revision)
+}
+
+// buildResourceWithState builds a ResourceWithState from a Resource
+func (m *ResourceManager) buildResourceWithState(ctx context.Context, resource *models.Resource) *ResourceWithState {
+	var appliedState models.ResourceAppliedState
+
+	m.DB.
+		WithContext(ctx).
+		Where("resource_id = ?", resource.ID).
+		First(&appliedState)
+
+	var currentState models.ResourceCurrentState
+
+	m.DB.
+		WithContext(ctx).
+		Where("resource_id = ?", resource.ID).
+		First(&currentState)
+
+	result := &ResourceWithState{
+		Resource: *resource,
+		Status:   ResourceStatusPending,
+		Health:   k8s.HealthUnknown,
+	}
+
+	if appliedState.ID != uuid.Nil {
+		result.AppliedState = &appliedState
+
+		if appliedState.MissingSince != nil {
+			result.Status = ResourceStatusMissing
+		} else if appliedState.Generation == resource.Generation {
+			result.Status = ResourceStatusSynced
+		} else if appliedState.Status == "error" && appliedState.LastAttemptedGeneration == resource.Generation {
+			result.Status = ResourceStatusError
+		} else {
+			result.Status = ResourceStatusOutOfSync
+		}
+	}
+
+	if currentState.ID != uuid.Nil {
+		result.CurrentState = &currentState
+
+		if currentState.Health != "" {
+			result.Health = currentState.Health
+		}
+	}
+
+	return result
+}
+
 // CreateFromTemplate creates a resource from a template
 func (m *ResourceManager) CreateFromTemplate(ctx context.Context, clusterID string, tmpl Template) (*ResourceWithState, error) {
 	kind, apiVersion, namespace, name, spec, err := tmpl.Build()
@@ -274,7 +430,7 @@ func (m *ResourceManager) GetByKey(ctx context.Context, clusterID, namespace, ki
 
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("resource not found")
+			return nil, ErrResourceNotFound
 		}
 		return nil, fmt.Errorf("failed to get resource: %w", err)
 	}
@@ -284,6 +440,27 @@ func (m *ResourceManager) GetByKey(ctx context.Context, clusterID, namespace, ki
 
 // Upsert creates or updates a resource atomically using INSERT ON CONFLICT
 func (m *ResourceManager) Upsert(ctx context.Context, req CreateResourceRequest) (*ResourceWithState, error) {
+	return m.upsert(ctx, req, nil, nil)
+}
+
+// UpsertDerived creates or updates the resource derived from a global resource in one
+// cluster, like Upsert, and links it to the global resource. The revision mirrors the
+// global resource's instead of being incremented, so re-syncing an unchanged spec does not
+// bump the generation. It returns ErrResourceConflict instead of taking over a resource with
+// the same key that is local or derived from another global resource.
+func (m *ResourceManager) UpsertDerived(ctx context.Context, globalResourceID uuid.UUID, req CreateResourceRequest, revision int) (*ResourceWithState, error) {
+	return m.upsert(ctx, req, &revision, &globalResourceID)
+}
+
+// upsert inserts the resource or updates it by key. A nil revision starts at 1 and is
+// incremented on update; a nil globalResourceID keeps the existing link.
+func (m *ResourceManager) upsert(ctx context.Context, req CreateResourceRequest, revision *int, globalResourceID *uuid.UUID) (*ResourceWithState, error) {
+	err := k8s.ValidateScope(req.Kind, req.APIVersion, req.Namespace)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResource, err)
+	}
+
 	resource := models.Resource{
 		ID:          uuid.Must(uuid.NewV7()),
 		ClusterID:   req.ClusterID,
@@ -294,25 +471,57 @@ func (m *ResourceManager) Upsert(ctx context.Context, req CreateResourceRequest)
 		DesiredSpec: req.DesiredSpec,
 		Generation:  1,
 		Revision:    1,
+		SelfHeal:    req.SelfHeal != nil && *req.SelfHeal,
+
+		GlobalResourceID: globalResourceID,
 	}
 
-	err := m.DB.
-		WithContext(ctx).
+	if revision != nil {
+		resource.Revision = *revision
+	}
+
+	tx := m.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	err = setAuthor(ctx, tx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// A derived upsert only updates a resource derived from the same global resource, never
+	// a local one that happens to have the same key
+	result := tx.
 		Exec(`
-			INSERT INTO k_resources (id, cluster_id, namespace, kind, name, api_version, desired_spec, generation, revision, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())
+			INSERT INTO k_resources (id, cluster_id, namespace, kind, name, api_version, desired_spec, generation, revision, self_heal, global_resource_id, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())
 			ON CONFLICT (cluster_id, namespace, kind, name) WHERE deleted_at IS NULL
 			DO UPDATE SET
 				api_version = EXCLUDED.api_version,
 				desired_spec = EXCLUDED.desired_spec,
-				revision = k_resources.revision + 1,
+				revision = COALESCE(?::int, k_resources.revision + 1),
+				self_heal = COALESCE(?::boolean, k_resources.self_heal),
+				global_resource_id = COALESCE(EXCLUDED.global_resource_id, k_resources.global_resource_id),
 				updated_at = NOW()
+			WHERE EXCLUDED.global_resource_id IS NULL
+			OR k_resources.global_resource_id = EXCLUDED.global_resource_id
 		`, resource.ID, resource.ClusterID, resource.Namespace, resource.Kind, resource.Name,
-			resource.APIVersion, resource.DesiredSpec, resource.Generation, resource.Revision).
-		Error
+			resource.APIVersion, resource.DesiredSpec, resource.Generation, resource.Revision, resource.SelfHeal, resource.GlobalResourceID,
+			revision, req.SelfHeal)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to upsert resource: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: %s %s/%s in cluster %s is not derived from global resource %s",
+			ErrResourceConflict, req.Kind, req.Namespace, req.Name, req.ClusterID, globalResourceID)
+	}
+
+	err = tx.Commit().Error
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to upsert resource: %w", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return m.GetByKey(ctx, req.ClusterID, req.Namespace, req.Kind, req.Name)
@@ -0,0 +1,239 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/models"
+)
+
+// clusterRollout is how far one targeted cluster got with the current generation of a
+// global resource, judged by its synced state and the applied state of its derived resource
+type clusterRollout struct {
+	GlobalResourceID uuid.UUID
+	ClusterID        string
+	Labels           map[string]string `gorm:"serializer:json"`
+	SyncedGeneration *int
+	Applied          bool // the derived resource's latest generation was applied
+	Failed           bool // the derived resource's latest generation failed to apply, or it is missing
+}
+
+// Rollouts returns the rollout status of the current generation of each of the given
+// global resources that has a rollout strategy, keyed by global resource ID. Deleted and
+// unknown global resources are left out.
+func (m *GlobalResourceManager) Rollouts(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*RolloutStatus, error) {
+	if len(ids) == 0 {
+		return map[uuid.UUID]*RolloutStatus{}, nil
+	}
+
+	var globalResources []models.GlobalResource
+
+	err := m.DB.
+		WithContext(ctx).
+		Where("id IN ? AND rollout_strategy IS NOT NULL", ids).
+		Find(&globalResources).
+		Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get global resources: %w", err)
+	}
+
+	return m.rolloutStatuses(ctx, globalResources)
+}
+
+func (m *GlobalResourceManager) rolloutStatus(ctx context.Context, gr *models.GlobalResource) (*RolloutStatus, error) {
+	statuses, err := m.rolloutStatuses(ctx, []models.GlobalResource{*gr})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return statuses[gr.ID], nil
+}
+
+// rolloutStatuses evaluates the rollouts of several global resources with one progress
+// query. Global resources without a rollout strategy get no entry.
+func (m *GlobalResourceManager) rolloutStatuses(ctx context.Context, globalResources []models.GlobalResource) (map[uuid.UUID]*RolloutStatus, error) {
+	statuses := make(map[uuid.UUID]*RolloutStatus)
+
+	var ids []uuid.UUID
+
+	for _, gr := range globalResources {
+		if gr.RolloutStrategy != nil {
+			ids = append(ids, gr.ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return statuses, nil
+	}
+
+	var clusters []clusterRollout
+
+	// A cluster that synced the generation but has no derived resource (deleted, or never
+	// created) counts as failed, so it cannot hold its stage forever
+	err := m.DB.
+		WithContext(ctx).
+		Raw(`
+			SELECT gr.id AS global_resource_id, c.id AS cluster_id, c.labels, ss.synced_generation,
+				COALESCE(a.generation >= r.generation, false) AS applied,
+				COALESCE(a.status = 'error' AND a.last_attempted_generation = r.generation, r.id IS NULL) AS failed
+			FROM k_global_resources gr
+			JOIN k_clusters c
+				ON kontrol_selector_matches(gr.cluster_selector, c.labels)
+			LEFT JOIN k_global_resource_synced_states ss
+				ON ss.global_resource_id = gr.id
+				AND ss.cluster_id = c.id
+				AND ss.deleted_at IS NULL
+			LEFT JOIN k_resources r
+				ON r.cluster_id = c.id
+				AND r.global_resource_id = gr.id
+				AND r.deleted_at IS NULL
+			LEFT JOIN k_resource_applied_states a
+				ON a.resource_id = r.id
+				AND a.deleted_at IS NULL
+			WHERE gr.id IN ?
+			ORDER BY c.id ASC
+		`, ids).
+		Scan(&clusters).
+		Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rollout progress: %w", err)
+	}
+
+	byGlobalResource := make(map[uuid.UUID][]clusterRollout)
+
+	for _, cluster := range clusters {
+		byGlobalResource[cluster.GlobalResourceID] = append(byGlobalResource[cluster.GlobalResourceID], cluster)
+	}
+
+	for _, gr := range globalResources {
+		if gr.RolloutStrategy != nil {
+			statuses[gr.ID] = evaluateRollout(gr.RolloutStrategy, gr.Generation, byGlobalResource[gr.ID])
+		}
+	}
+
+	return statuses, nil
+}
+
+// evaluateRollout works out the current stage and which waiting clusters of it may pick
+// up the generation now. A cluster holds its stage until it applied the generation; with
+// PauseOnFailure a failed apply or a missing derived resource holds it and pauses the
+// whole rollout, otherwise it counts as done.
+func evaluateRollout(strategy *models.RolloutStrategy, generation int, clusters []clusterRollout) *RolloutStatus {
+	status := &RolloutStatus{
+		Stages: len(strategy.Waves) + 1,
+		Paused: strategy.Paused,
+	}
+
+	if len(strategy.CanaryClusterIDs) > 0 {
+		status.Stages++
+	}
+
+	status.Stage = status.Stages
+	waiting := make(map[int][]string)
+
+	for _, cluster := range clusters {
+		stage := rolloutStage(strategy, &cluster)
+
+		switch {
+		case cluster.SyncedGeneration == nil || *cluster.SyncedGeneration < generation:
+			waiting[stage] = append(waiting[stage], cluster.ClusterID)
+		case cluster.Applied:
+			continue
+		case cluster.Failed:
+			status.FailedClusters = append(status.FailedClusters, cluster.ClusterID)
+
+			if !strategy.PauseOnFailure {
+				continue
+			}
+		default:
+			status.RollingOut = append(status.RollingOut, cluster.ClusterID)
+		}
+
+		status.Stage = min(status.Stage, stage)
+	}
+
+	if strategy.PauseOnFailure && len(status.FailedClusters) > 0 {
+		status.Paused = true
+	}
+
+	if status.Paused {
+		return status
+	}
+
+	admitted := waiting[status.Stage]
+
+	if strategy.MaxConcurrent > 0 {
+		slots := max(strategy.MaxConcurrent-len(status.RollingOut), 0)
+		admitted = admitted[:min(slots, len(admitted))]
+	}
+
+	status.Admitted = admitted
+
+	return status
+}
+
+// rolloutStage returns the stage a cluster rolls out in: 0 for canaries, then one per
+// wave, then the final stage for clusters no wave matches
+func rolloutStage(strategy *models.RolloutStrategy, cluster *clusterRollout) int {
+	stage := 0
+
+	if len(strategy.CanaryClusterIDs) > 0 {
+		if slices.Contains(strategy.CanaryClusterIDs, cluster.ClusterID) {
+			return 0
+		}
+
+		stage = 1
+	}
+
+	for i := range strategy.Waves {
+		if ClusterSelectorMatches(&strategy.Waves[i], cluster.Labels) {
+			return stage + i
+		}
+	}
+
+	return stage + len(strategy.Waves)
+}
+
+// validateRolloutStrategy checks the wave selectors and limits of a rollout strategy
+func validateRolloutStrategy(strategy *models.RolloutStrategy) error {
+	if strategy == nil {
+		return nil
+	}
+
+	if strategy.MaxConcurrent < 0 {
+		return fmt.Errorf("%w: rollout max_concurrent must not be negative", ErrInvalidResource)
+	}
+
+	for _, id := range strategy.CanaryClusterIDs {
+		if id == "" {
+			return fmt.Errorf("%w: rollout canary cluster id must not be empty", ErrInvalidResource)
+		}
+	}
+
+	for i := range strategy.Waves {
+		err := validateClusterSelector(&strategy.Waves[i])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rolloutStrategyJSON encodes a rollout strategy for raw SQL; a nil strategy becomes NULL
+func rolloutStrategyJSON(strategy *models.RolloutStrategy) []byte {
+	if strategy == nil {
+		return nil
+	}
+
+	data, _ := json.Marshal(strategy)
+
+	return data
+}
@@ -0,0 +1,122 @@
+package manager
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/targc/kontrol/pkg/models"
+)
+
+func TestEvaluateRollout(t *testing.T) {
+	const generation = 2
+
+	synced := func(g int) *int { return &g }
+
+	waiting := func(id string, labels map[string]string) clusterRollout {
+		return clusterRollout{ClusterID: id, Labels: labels}
+	}
+
+	applied := func(id string, labels map[string]string) clusterRollout {
+		return clusterRollout{ClusterID: id, Labels: labels, SyncedGeneration: synced(generation), Applied: true}
+	}
+
+	rollingOut := func(id string, labels map[string]string) clusterRollout {
+		return clusterRollout{ClusterID: id, Labels: labels, SyncedGeneration: synced(generation)}
+	}
+
+	failed := func(id string, labels map[string]string) clusterRollout {
+		return clusterRollout{ClusterID: id, Labels: labels, SyncedGeneration: synced(generation), Failed: true}
+	}
+
+	staging := map[string]string{"env": "staging"}
+	prod := map[string]string{"env": "prod"}
+
+	staged := &models.RolloutStrategy{
+		CanaryClusterIDs: []string{"canary"},
+		Waves:            []models.ClusterSelector{{MatchLabels: staging}},
+	}
+
+	tests := []struct {
+		name     string
+		strategy *models.RolloutStrategy
+		clusters []clusterRollout
+		want     *RolloutStatus
+	}{
+		{
+			name:     "canaries go first",
+			strategy: staged,
+			clusters: []clusterRollout{waiting("canary", prod), waiting("stg", staging), waiting("prd", prod)},
+			want:     &RolloutStatus{Stage: 0, Stages: 3, Admitted: []string{"canary"}},
+		},
+		{
+			name:     "stage holds while a cluster is rolling out",
+			strategy: staged,
+			clusters: []clusterRollout{rollingOut("canary", prod), waiting("stg", staging), waiting("prd", prod)},
+			want:     &RolloutStatus{Stage: 0, Stages: 3, RollingOut: []string{"canary"}},
+		},
+		{
+			name:     "wave follows applied canaries",
+			strategy: staged,
+			clusters: []clusterRollout{applied("canary", prod), waiting("stg", staging), waiting("prd", prod)},
+			want:     &RolloutStatus{Stage: 1, Stages: 3, Admitted: []string{"stg"}},
+		},
+		{
+			name:     "remaining clusters go last",
+			strategy: staged,
+			clusters: []clusterRollout{applied("canary", prod), applied("stg", staging), waiting("prd", prod)},
+			want:     &RolloutStatus{Stage: 2, Stages: 3, Admitted: []string{"prd"}},
+		},
+		{
+			name:     "rollout complete",
+			strategy: staged,
+			clusters: []clusterRollout{applied("canary", prod), applied("stg", staging), applied("prd", prod)},
+			want:     &RolloutStatus{Stage: 3, Stages: 3},
+		},
+		{
+			name:     "max concurrent counts clusters rolling out",
+			strategy: &models.RolloutStrategy{MaxConcurrent: 2},
+			clusters: []clusterRollout{rollingOut("a", prod), waiting("b", prod), waiting("c", prod), waiting("d", prod)},
+			want:     &RolloutStatus{Stage: 0, Stages: 1, RollingOut: []string{"a"}, Admitted: []string{"b"}},
+		},
+		{
+			name:     "max concurrent reached",
+			strategy: &models.RolloutStrategy{MaxConcurrent: 1},
+			clusters: []clusterRollout{rollingOut("a", prod), waiting("b", prod)},
+			want:     &RolloutStatus{Stage: 0, Stages: 1, RollingOut: []string{"a"}, Admitted: []string{}},
+		},
+		{
+			name:     "failure pauses with pause on failure",
+			strategy: &models.RolloutStrategy{CanaryClusterIDs: []string{"canary"}, PauseOnFailure: true},
+			clusters: []clusterRollout{failed("canary", prod), waiting("prd", prod)},
+			want:     &RolloutStatus{Stage: 0, Stages: 2, Paused: true, FailedClusters: []string{"canary"}},
+		},
+		{
+			name:     "failure counts as done without pause on failure",
+			strategy: &models.RolloutStrategy{CanaryClusterIDs: []string{"canary"}},
+			clusters: []clusterRollout{failed("canary", prod), waiting("prd", prod)},
+			want:     &RolloutStatus{Stage: 1, Stages: 2, FailedClusters: []string{"canary"}, Admitted: []string{"prd"}},
+		},
+		{
+			name:     "paused strategy admits nobody",
+			strategy: &models.RolloutStrategy{Paused: true},
+			clusters: []clusterRollout{waiting("a", prod)},
+			want:     &RolloutStatus{Stage: 0, Stages: 1, Paused: true},
+		},
+		{
+			name:     "clusters behind on an older generation are waiting",
+			strategy: &models.RolloutStrategy{CanaryClusterIDs: []string{"canary"}},
+			clusters: []clusterRollout{{ClusterID: "canary", SyncedGeneration: synced(generation - 1), Applied: true}, waiting("prd", prod)},
+			want:     &RolloutStatus{Stage: 0, Stages: 2, Admitted: []string{"canary"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateRollout(tt.strategy, generation, tt.clusters)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("evaluateRollout() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package manager
 
 import (
 	"encoding/json"
+	"slices"
+	"time"
 
 	"github.com/targc/kontrol/pkg/models"
 )
@@ -14,16 +16,39 @@ type CreateResourceRequest struct {
 	Name        string          `json:"name"`
 	APIVersion  string          `json:"api_version"`
 	DesiredSpec json.RawMessage `json:"desired_spec"`
+	SelfHeal    *bool           `json:"self_heal,omitempty"` // nil is off on create and keeps the current setting on upsert
 }
 
 // UpdateResourceRequest represents a request to update a resource
 type UpdateResourceRequest struct {
 	DesiredSpec json.RawMessage `json:"desired_spec"`
+	Revision    *int            `json:"revision,omitempty"`
 }
 
+// RollbackRequest represents a request to restore the desired spec of a historical revision
+type RollbackRequest struct {
+	Revision int `json:"revision"`
+}
+
+// SetSelfHealRequest represents a request to turn self-heal on or off
+type SetSelfHealRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// Resource status values derived from the resource and its applied state
+const (
+	ResourceStatusPending   = "pending"
+	ResourceStatusOutOfSync = "out-of-sync"
+	ResourceStatusSynced    = "synced"
+	ResourceStatusError     = "error"   // the latest generation failed to apply
+	ResourceStatusMissing   = "missing" // the applied object was deleted out-of-band
+)
+
 // ResourceWithState represents a resource with its applied and current states
 type ResourceWithState struct {
 	Resource     models.Resource              `json:"resource"`
+	Status       string                       `json:"status"`
+	Health       string                       `json:"health"` // healthy / progressing / degraded / unknown
 	AppliedState *models.ResourceAppliedState `json:"applied_state,omitempty"`
 	CurrentState *models.ResourceCurrentState `json:"current_state,omitempty"`
 }
@@ -35,6 +60,32 @@ type CreateGlobalResourceRequest struct {
 	Name        string          `json:"name"`
 	APIVersion  string          `json:"api_version"`
 	DesiredSpec json.RawMessage `json:"desired_spec"`
+	SelfHeal    *bool           `json:"self_heal,omitempty"` // nil is off on create and keeps the current setting on upsert
+
+	ClusterSelector *models.ClusterSelector  `json:"cluster_selector,omitempty"` // nil targets every cluster
+	Overrides       []models.ClusterOverride `json:"overrides,omitempty"`
+	RolloutStrategy *models.RolloutStrategy  `json:"rollout_strategy,omitempty"` // nil rolls out to every cluster at once
+}
+
+// SetOverridesRequest represents a request to replace the per-cluster overrides of a global resource
+type SetOverridesRequest struct {
+	Overrides []models.ClusterOverride `json:"overrides"`
+}
+
+// SetClusterSelectorRequest represents a request to change which clusters a global resource targets
+type SetClusterSelectorRequest struct {
+	ClusterSelector *models.ClusterSelector `json:"cluster_selector"` // nil targets every cluster
+}
+
+// SetRolloutStrategyRequest represents a request to change how a global resource rolls out
+type SetRolloutStrategyRequest struct {
+	RolloutStrategy *models.RolloutStrategy `json:"rollout_strategy"` // nil rolls out to every cluster at once
+}
+
+// UpdateGlobalResourceRequest represents a request to update a global resource
+type UpdateGlobalResourceRequest struct {
+	DesiredSpec json.RawMessage `json:"desired_spec"`
+	Revision    *int            `json:"revision,omitempty"`
 }
 
 // ClusterSyncStatus represents sync status for a single cluster
@@ -42,6 +93,7 @@ type ClusterSyncStatus struct {
 	ClusterID        string `json:"cluster_id"`
 	SyncedGeneration int    `json:"synced_generation"`
 	IsSynced         bool   `json:"is_synced"`
+	Conflict         string `json:"conflict,omitempty"` // why the derived resource could not be upserted
 }
 
 // GlobalResourceWithSyncStatus represents a global resource with its sync status across clusters
@@ -50,4 +102,62 @@ type GlobalResourceWithSyncStatus struct {
 	TotalClusters   int                   `json:"total_clusters"`
 	SyncedClusters  int                   `json:"synced_clusters"`
 	ClusterStatuses []ClusterSyncStatus   `json:"cluster_statuses,omitempty"`
+	Rollout         *RolloutStatus        `json:"rollout,omitempty"` // only set with a rollout strategy
+}
+
+// RolloutStatus describes how far the current generation of a global resource rolled out.
+// Stages are numbered from 0; Stage equals Stages once every stage is done.
+type RolloutStatus struct {
+	Stage          int      `json:"stage"`
+	Stages         int      `json:"stages"`
+	Paused         bool     `json:"paused"`                    // paused by the strategy or by a failed apply
+	Admitted       []string `json:"admitted,omitempty"`        // clusters allowed to pick up the generation now
+	RollingOut     []string `json:"rolling_out,omitempty"`     // clusters that picked it up but have not applied it yet
+	FailedClusters []string `json:"failed_clusters,omitempty"` // clusters where it failed to apply
+}
+
+// Admits reports whether a cluster may pick up the current generation now
+func (s *RolloutStatus) Admits(clusterID string) bool {
+	return slices.Contains(s.Admitted, clusterID)
+}
+
+// CreateAdminTokenRequest represents a request to mint a new admin token
+type CreateAdminTokenRequest struct {
+	Name      string     `json:"name"`
+	Scope     string     `json:"scope"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// AdminTokenWithSecret represents a newly minted admin token; the plaintext token is only returned once
+type AdminTokenWithSecret struct {
+	AdminToken models.AdminToken `json:"admin_token"`
+	Token      string            `json:"token"`
+}
+
+// CreateClusterRequest represents a request to pre-provision a cluster
+type CreateClusterRequest struct {
+	ID     string            `json:"id"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// SetClusterLabelsRequest represents a request to replace the labels of a cluster
+type SetClusterLabelsRequest struct {
+	Labels map[string]string `json:"labels"`
+}
+
+// CreateClusterAPIKeyRequest represents a request to mint a new cluster API key
+type CreateClusterAPIKeyRequest struct {
+	Name string `json:"name"`
+}
+
+// RotateClusterAPIKeyRequest represents a request to replace a cluster API key.
+// The old key keeps working for OverlapSeconds so workers can be rolled onto the new key.
+type RotateClusterAPIKeyRequest struct {
+	OverlapSeconds int `json:"overlap_seconds"`
+}
+
+// ClusterAPIKeyWithSecret represents a newly minted cluster API key; the plaintext key is only returned once
+type ClusterAPIKeyWithSecret struct {
+	APIKey models.ClusterAPIKey `json:"api_key"`
+	Key    string               `json:"key"`
 }
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Admin token scopes, from least to most privileged
+const (
+	AdminScopeReadOnly = "read-only"
+	AdminScopeWrite    = "write"
+	AdminScopeAdmin    = "admin"
+)
+
+type AdminToken struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	Name       string         `gorm:"type:varchar(100);not null" json:"name"`
+	TokenHash  string         `gorm:"type:varchar(255);not null" json:"-"`
+	LookupID   *string        `gorm:"type:varchar(32);uniqueIndex" json:"-"` // non-secret part of the token used to find its hash; nil for bootstrap tokens
+	Scope      string         `gorm:"type:varchar(50);not null" json:"scope"`
+	ExpiresAt  *time.Time     `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time     `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+func (AdminToken) TableName() string {
+	return "k_admin_tokens"
+}
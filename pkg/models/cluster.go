@@ -8,6 +8,12 @@ type Cluster struct {
 	ID        string    `gorm:"primaryKey;type:varchar(100)" json:"id"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	Labels map[string]string `gorm:"type:jsonb;serializer:json;not null;default:'{}'" json:"labels"` // matched by global resource cluster selectors
+
+	// Leader lease: only the worker holding it reconciles, other replicas stand by
+	LeaderID             string     `gorm:"type:varchar(255);not null;default:''" json:"leader_id"`
+	LeaderLeaseExpiresAt *time.Time `json:"leader_lease_expires_at"`
 }
 
 func (Cluster) TableName() string {
@@ -11,7 +11,8 @@ type ClusterAPIKey struct {
 	ID        uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
 	ClusterID string         `gorm:"type:varchar(100);not null;index" json:"cluster_id"`
 	KeyHash   string         `gorm:"type:varchar(255);not null" json:"-"`
-	Name      string         `gorm:"type:varchar(100)" json:"name"`
+	Name      string         `gorm:"type:varchar(100);index" json:"name"`
+	ExpiresAt *time.Time     `json:"expires_at,omitempty"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
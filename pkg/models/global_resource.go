@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,22 +9,71 @@ import (
 )
 
 type GlobalResource struct {
-	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
-	Namespace  string `gorm:"type:varchar(255);not null"`
-	Kind       string `gorm:"type:varchar(255);not null"`
-	Name       string `gorm:"type:varchar(255);not null"`
-	APIVersion string `gorm:"type:varchar(100)"`
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Namespace  string    `gorm:"type:varchar(255);not null" json:"namespace"`
+	Kind       string    `gorm:"type:varchar(255);not null" json:"kind"`
+	Name       string    `gorm:"type:varchar(255);not null" json:"name"`
+	APIVersion string    `gorm:"type:varchar(100)" json:"api_version"`
 
-	DesiredSpec []byte `gorm:"type:jsonb;not null"`
+	DesiredSpec json.RawMessage `gorm:"type:jsonb;not null" json:"desired_spec"`
 
-	Generation int `gorm:"default:1;not null"`
-	Revision   int `gorm:"default:1;not null"`
+	Generation int `gorm:"default:1;not null" json:"generation"`
+	Revision   int `gorm:"default:1;not null" json:"revision"`
 
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	SelfHeal bool `gorm:"default:false;not null" json:"self_heal"` // inherited by derived resources
+
+	ClusterSelector *ClusterSelector  `gorm:"type:jsonb;serializer:json" json:"cluster_selector"` // nil targets every cluster
+	Overrides       []ClusterOverride `gorm:"type:jsonb;serializer:json" json:"overrides"`        // applied in order to the spec derived for matching clusters
+	RolloutStrategy *RolloutStrategy  `gorm:"type:jsonb;serializer:json" json:"rollout_strategy"` // nil rolls a new generation out to every cluster at once
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 }
 
 func (GlobalResource) TableName() string {
 	return "k_global_resources"
 }
+
+// ClusterSelector selects clusters by their labels, like a Kubernetes label selector:
+// a cluster matches when it has every MatchLabels entry and satisfies every expression
+type ClusterSelector struct {
+	MatchLabels      map[string]string            `json:"match_labels,omitempty"`
+	MatchExpressions []ClusterSelectorRequirement `json:"match_expressions,omitempty"`
+}
+
+// ClusterSelectorRequirement is a selector expression; Operator is one of
+// In, NotIn, Exists or DoesNotExist, and Values is only set for In and NotIn
+type ClusterSelectorRequirement struct {
+	Key      string   `json:"key"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values,omitempty"`
+}
+
+// ClusterOverride patches the desired spec derived for one cluster, or for every cluster
+// matched by a selector. Exactly one of ClusterID and ClusterSelector is set.
+type ClusterOverride struct {
+	ClusterID       string           `json:"cluster_id,omitempty"`
+	ClusterSelector *ClusterSelector `json:"cluster_selector,omitempty"`
+	Patch           json.RawMessage  `json:"patch"` // JSON merge patch (RFC 7386) applied to desired_spec
+}
+
+// RolloutStrategy staggers how targeted clusters pick up a new generation. Clusters roll
+// out in stages: the canary clusters, then each wave in order, then every remaining
+// cluster. A stage starts once every cluster of the earlier stages has applied the
+// generation.
+type RolloutStrategy struct {
+	CanaryClusterIDs []string          `json:"canary_cluster_ids,omitempty"`
+	Waves            []ClusterSelector `json:"waves,omitempty"`          // a cluster belongs to the first wave it matches
+	MaxConcurrent    int               `json:"max_concurrent,omitempty"` // clusters applying the generation at once; 0 is unlimited
+	PauseOnFailure   bool              `json:"pause_on_failure"`         // hold the rollout while a derived resource fails to apply
+	Paused           bool              `json:"paused"`                   // hold the rollout until unpaused
+}
+
+// Cluster selector operators
+const (
+	SelectorOpIn           = "In"
+	SelectorOpNotIn        = "NotIn"
+	SelectorOpExists       = "Exists"
+	SelectorOpDoesNotExist = "DoesNotExist"
+)
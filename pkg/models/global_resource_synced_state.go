@@ -8,14 +8,19 @@ import (
 )
 
 type GlobalResourceSyncedState struct {
-	ID               uuid.UUID `gorm:"type:uuid;primaryKey"`
-	GlobalResourceID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_global_cluster"`
-	ClusterID        string `gorm:"type:varchar(100);not null;uniqueIndex:idx_global_cluster"`
-	SyncedGeneration int    `gorm:"default:1;not null"`
+	ID               uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	GlobalResourceID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_global_cluster" json:"global_resource_id"`
+	ClusterID        string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_global_cluster" json:"cluster_id"`
+	SyncedGeneration int       `gorm:"default:1;not null" json:"synced_generation"`
 
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	ClusterLabels map[string]string `gorm:"type:jsonb;serializer:json" json:"cluster_labels"` // labels the overrides were evaluated against
+
+	Conflict   string     `gorm:"type:text" json:"conflict,omitempty"` // why the derived resource could not be upserted
+	ConflictAt *time.Time `json:"conflict_at,omitempty"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 }
 
 func (GlobalResourceSyncedState) TableName() string {
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,11 +16,15 @@ type Resource struct {
 	Name       string         `gorm:"type:varchar(255);not null" json:"name"`
 	APIVersion string         `gorm:"type:varchar(100)" json:"api_version"`
 
-	DesiredSpec []byte         `gorm:"type:jsonb;not null" json:"desired_spec"`
+	DesiredSpec json.RawMessage `gorm:"type:jsonb;not null" json:"desired_spec"`
 
 	Generation  int            `gorm:"default:1;not null" json:"generation"`
 	Revision    int            `gorm:"default:1;not null" json:"revision"`
 
+	SelfHeal    bool           `gorm:"default:false;not null" json:"self_heal"` // re-apply on drift or out-of-band deletion
+
+	GlobalResourceID *uuid.UUID `gorm:"type:uuid;index" json:"global_resource_id,omitempty"` // set when derived from a global resource
+
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
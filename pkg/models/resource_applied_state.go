@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,13 +12,25 @@ type ResourceAppliedState struct {
 	ID         uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
 	ResourceID uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex" json:"resource_id"`
 
-	Spec         []byte         `gorm:"type:jsonb" json:"spec"`
-	Generation   int            `json:"generation"`
-	Revision     int            `json:"revision"`
+	Spec         json.RawMessage `gorm:"type:jsonb" json:"spec"`
+	Generation   int             `json:"generation"`
+	Revision     int             `json:"revision"`
 
+	// Spec, Generation and Revision above only change on a successful apply.
+	// Status and ErrorMessage describe the last attempt.
 	Status       string         `gorm:"type:varchar(50)" json:"status"`
 	ErrorMessage *string        `gorm:"type:text" json:"error_message,omitempty"`
 
+	LastAttemptedGeneration int        `json:"last_attempted_generation"`
+	LastAttemptAt           *time.Time `json:"last_attempt_at,omitempty"`
+	AttemptCount            int        `gorm:"default:0;not null" json:"attempt_count"`
+	RetryCount              int        `gorm:"default:0;not null" json:"retry_count"`
+	NextAttemptAt           *time.Time `gorm:"index" json:"next_attempt_at,omitempty"`
+
+	HealRequestedAt *time.Time `json:"heal_requested_at,omitempty"` // set on drift or out-of-band deletion of a self-healing resource
+	LastHealedAt    *time.Time `json:"last_healed_at,omitempty"`
+	MissingSince    *time.Time `json:"missing_since,omitempty"` // applied object was deleted out-of-band and not re-created yet
+
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
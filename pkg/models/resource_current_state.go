@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,10 +12,20 @@ type ResourceCurrentState struct {
 	ID         uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
 	ResourceID uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex" json:"resource_id"`
 
-	Spec                []byte         `gorm:"type:jsonb" json:"spec"`
-	Generation          int            `json:"generation"`
-	Revision            int            `json:"revision"`
-	K8sResourceVersion  string         `gorm:"type:varchar(100)" json:"k8s_resource_version"`
+	Spec                json.RawMessage `gorm:"type:jsonb" json:"spec"`
+	Object              json.RawMessage `gorm:"type:jsonb" json:"object"`     // full live object, redacted by the watcher
+	Status              json.RawMessage `gorm:"type:jsonb" json:"status"`     // .status of the live object
+	Conditions          json.RawMessage `gorm:"type:jsonb" json:"conditions"` // .status.conditions of the live object
+	Generation          int             `json:"generation"`
+	Revision            int             `json:"revision"`
+	K8sResourceVersion  string          `gorm:"type:varchar(100)" json:"k8s_resource_version"`
+
+	Health              string         `gorm:"type:varchar(50)" json:"health"` // healthy / progressing / degraded
+	HealthMessage       string         `gorm:"type:text" json:"health_message,omitempty"`
+
+	Drifted             bool           `gorm:"default:false;not null;index" json:"drifted"`
+	DriftSummary        string         `gorm:"type:text" json:"drift_summary,omitempty"` // one difference per line
+	DriftCheckedAt      *time.Time     `json:"drift_checked_at,omitempty"`
 
 	CreatedAt           time.Time      `json:"created_at"`
 	UpdatedAt           time.Time      `json:"updated_at"`
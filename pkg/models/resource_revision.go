@@ -0,0 +1,27 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ResourceRevision is an append-only snapshot of a resource's desired spec,
+// written by a DB trigger whenever the spec or revision changes
+type ResourceRevision struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ResourceID uuid.UUID `gorm:"type:uuid;not null;index:idx_resource_revisions_resource" json:"resource_id"`
+
+	DesiredSpec json.RawMessage `gorm:"type:jsonb;not null" json:"desired_spec"`
+
+	Generation int `gorm:"not null;index:idx_resource_revisions_resource" json:"generation"`
+	Revision   int `gorm:"not null" json:"revision"`
+
+	Author    string    `gorm:"type:varchar(255);not null" json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (ResourceRevision) TableName() string {
+	return "k_resource_revisions"
+}
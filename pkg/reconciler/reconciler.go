@@ -5,25 +5,43 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/targc/kontrol/pkg/apiclient"
 	"github.com/targc/kontrol/pkg/k8s"
 	"github.com/targc/kontrol/pkg/models"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 )
 
+// batchSize is the page size used when fetching out-of-sync and deleted resources
+const batchSize = 100
+
 type Reconciler struct {
 	Client        *apiclient.Client
 	ClusterID     string
 	DynamicClient dynamic.Interface
+	Resolver      *k8s.GVRResolver
+	Concurrency   int         // number of resources applied or deleted in parallel
+	Fence         func() bool // reports whether this replica may still write to the cluster; nil always may
+
+	PollInterval       time.Duration // delay between idle passes while the change event stream is down
+	StreamPollInterval time.Duration // delay between idle passes while change events are streaming
+
+	mu       sync.Mutex
+	inFlight map[uuid.UUID]struct{}
+	sem      chan struct{}  // one slot per worker
+	wg       sync.WaitGroup // tracks started items so shutdown can wait for them
+	wake     chan struct{}
 }
 
-func NewReconciler(client *apiclient.Client, clusterID, kubeconfig string) (*Reconciler, error) {
+func NewReconciler(client *apiclient.Client, clusterID, kubeconfig string, resolver *k8s.GVRResolver, concurrency int, pollInterval, streamPollInterval time.Duration) (*Reconciler, error) {
 	config, err := k8s.BuildConfig(kubeconfig)
 
 	if err != nil {
@@ -40,6 +58,15 @@ func NewReconciler(client *apiclient.Client, clusterID, kubeconfig string) (*Rec
 		Client:        client,
 		ClusterID:     clusterID,
 		DynamicClient: dynamicClient,
+		Resolver:      resolver,
+		Concurrency:   concurrency,
+
+		PollInterval:       pollInterval,
+		StreamPollInterval: streamPollInterval,
+
+		inFlight: make(map[uuid.UUID]struct{}),
+		sem:      make(chan struct{}, max(concurrency, 1)),
+		wake:     make(chan struct{}, 1),
 	}, nil
 }
 
@@ -49,42 +76,144 @@ func (r *Reconciler) Start(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
+			// Items already started run to completion, so wait for them before returning
+			r.wg.Wait()
 			log.Println("[Reconciler] Stopping reconciliation loop")
 			return
 		default:
 			r.reconcile(ctx)
-			time.Sleep(10 * time.Second)
+			r.wait(ctx)
 		}
 	}
 }
 
+// Wake starts the next reconciliation pass right away instead of after the poll interval
+func (r *Reconciler) Wake() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// wait blocks until Wake is called, the poll interval elapses or ctx is cancelled.
+// Polling slows down while change events are streaming, since they wake the loop anyway.
+func (r *Reconciler) wait(ctx context.Context) {
+	interval := r.PollInterval
+
+	if r.Client.Streaming() {
+		interval = r.StreamPollInterval
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-r.wake:
+	case <-time.After(interval):
+	}
+}
+
+// reconcile hands one page of out-of-sync resources and one page of deleted resources to
+// the worker pool without waiting for them to finish, so a slow apply or delete does not
+// hold up the next page. Resources still in flight are skipped, and the pages are widened
+// by the number in flight so they cannot crowd out waiting work.
 func (r *Reconciler) reconcile(ctx context.Context) {
+	limit := batchSize + r.inFlightCount()
+
 	// Fetch out-of-sync resources from API
-	outOfSyncResources, err := r.Client.ListOutOfSyncResources(ctx, 100)
+	outOfSyncResources, err := r.Client.ListOutOfSyncResources(ctx, limit)
 
 	if err != nil {
 		log.Printf("[Reconciler] Failed to fetch out-of-sync resources: %v", err)
 		return
 	}
 
-	for _, resource := range outOfSyncResources {
-		r.reconcileResource(ctx, &resource)
-	}
-
 	// Fetch deleted resources from API
-	deletedResources, err := r.Client.ListDeletedResources(ctx, 100)
+	deletedResources, err := r.Client.ListDeletedResources(ctx, limit)
 
 	if err != nil {
 		log.Printf("[Reconciler] Failed to fetch deleted resources: %v", err)
 		return
 	}
 
+	for _, resource := range outOfSyncResources {
+		r.dispatch(ctx, resource, r.reconcileResource, len(outOfSyncResources) == limit)
+	}
+
 	for _, resource := range deletedResources {
-		r.deleteResource(ctx, &resource)
+		r.dispatch(ctx, resource, r.deleteResource, len(deletedResources) == limit)
 	}
 }
 
-func (r *Reconciler) reconcileResource(ctx context.Context, resource *models.Resource) {
+// dispatch runs fn for a resource on the worker pool, blocking until a worker is free.
+// When the resource came from a full page and fn succeeds, the loop is woken to fetch
+// the next page right away, since more work is likely waiting.
+func (r *Reconciler) dispatch(ctx context.Context, resource models.Resource, fn func(context.Context, *models.Resource) bool, fullPage bool) {
+	// Skips resources still in flight from an earlier page, and a resource soft-deleted
+	// between the two list calls that shows up in both pages
+	if !r.acquire(resource.ID) {
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+		r.release(resource.ID)
+		return
+	case r.sem <- struct{}{}:
+	}
+
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+		defer func() { <-r.sem }()
+		defer r.release(resource.ID)
+
+		// Once the leader lease may have expired another replica can take over, so
+		// queued items must not write to the cluster anymore
+		if r.Fence != nil && !r.Fence() {
+			log.Printf("[Reconciler] Leader lease not held, skipping resource %s", resource.ID)
+			return
+		}
+
+		// Items already started run to completion on shutdown, so an apply is never cut off
+		// between the K8s patch and recording the applied state
+		if fn(context.WithoutCancel(ctx), &resource) && fullPage {
+			r.Wake()
+		}
+	}()
+}
+
+// acquire marks a resource as in flight, returning false if it already is
+func (r *Reconciler) acquire(id uuid.UUID) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.inFlight[id]; ok {
+		return false
+	}
+
+	r.inFlight[id] = struct{}{}
+
+	return true
+}
+
+// inFlightCount returns the number of resources being applied or deleted
+func (r *Reconciler) inFlightCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.inFlight)
+}
+
+// release clears the in-flight mark of a resource
+func (r *Reconciler) release(id uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.inFlight, id)
+}
+
+// reconcileResource applies a resource and returns true if it was applied successfully
+func (r *Reconciler) reconcileResource(ctx context.Context, resource *models.Resource) bool {
 	log.Printf("[Reconciler] Reconciling resource %s (gen=%d, rev=%d)", resource.ID, resource.Generation, resource.Revision)
 
 	var spec map[string]interface{}
@@ -107,27 +236,40 @@ func (r *Reconciler) reconcileResource(ctx context.Context, resource *models.Res
 	annotations["kontrol/revision"] = fmt.Sprintf("%d", resource.Revision)
 	obj.SetAnnotations(annotations)
 
-	gvr := k8s.GetGVR(resource.Kind, resource.APIVersion)
+	labels := obj.GetLabels()
+
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+
+	labels[k8s.ManagedLabel] = k8s.ManagedLabelValue
+	obj.SetLabels(labels)
 
 	patchData, err := json.Marshal(obj)
 
 	if err != nil {
 		log.Printf("[Reconciler] Failed to marshal resource %s: %v", resource.ID, err)
-		errMsg := err.Error()
+		r.recordApplyError(ctx, resource, err)
+		return false
+	}
 
-		updateErr := r.Client.UpsertAppliedState(ctx, resource.ID, &apiclient.UpsertAppliedStateRequest{
-			Status:       "error",
-			ErrorMessage: &errMsg,
-		})
+	mapping, err := r.Resolver.ResolveKind(resource.Kind, resource.APIVersion)
 
-		if updateErr != nil {
-			log.Printf("[Reconciler] Failed to update applied_state for resource %s: %v", resource.ID, updateErr)
-		}
+	if err != nil {
+		log.Printf("[Reconciler] Failed to resolve GVR for resource %s: %v", resource.ID, err)
+		r.recordApplyError(ctx, resource, err)
+		return false
+	}
 
-		return
+	resourceClient, err := k8s.ResourceClient(r.DynamicClient, mapping, resource.Namespace)
+
+	if err != nil {
+		log.Printf("[Reconciler] Scope mismatch for resource %s: %v", resource.ID, err)
+		r.recordApplyError(ctx, resource, err)
+		return false
 	}
 
-	_, err = r.DynamicClient.Resource(gvr).Namespace(resource.Namespace).Patch(
+	_, err = resourceClient.Patch(
 		ctx,
 		resource.Name,
 		types.ApplyPatchType,
@@ -140,18 +282,8 @@ func (r *Reconciler) reconcileResource(ctx context.Context, resource *models.Res
 
 	if err != nil {
 		log.Printf("[Reconciler] Failed to apply resource %s: %v", resource.ID, err)
-		errMsg := err.Error()
-
-		updateErr := r.Client.UpsertAppliedState(ctx, resource.ID, &apiclient.UpsertAppliedStateRequest{
-			Status:       "error",
-			ErrorMessage: &errMsg,
-		})
-
-		if updateErr != nil {
-			log.Printf("[Reconciler] Failed to update applied_state for resource %s: %v", resource.ID, updateErr)
-		}
-
-		return
+		r.recordApplyError(ctx, resource, err)
+		return false
 	}
 
 	resultBytes, _ := json.Marshal(obj)
@@ -166,31 +298,60 @@ func (r *Reconciler) reconcileResource(ctx context.Context, resource *models.Res
 
 	if err != nil {
 		log.Printf("[Reconciler] Failed to update applied_state for resource %s: %v", resource.ID, err)
-		return
+		return false
 	}
 
 	log.Printf("[Reconciler] Successfully applied resource %s (gen=%d, rev=%d)", resource.ID, resource.Generation, resource.Revision)
+
+	return true
 }
 
-func (r *Reconciler) deleteResource(ctx context.Context, resource *models.Resource) {
+// deleteResource deletes a resource from K8s and returns true if it was hard deleted
+func (r *Reconciler) deleteResource(ctx context.Context, resource *models.Resource) bool {
 	log.Printf("[Reconciler] Deleting resource %s from K8s", resource.ID)
 
-	gvr := k8s.GetGVR(resource.Kind, resource.APIVersion)
-
-	err := r.DynamicClient.Resource(gvr).Namespace(resource.Namespace).
-		Delete(ctx, resource.Name, metav1.DeleteOptions{})
-
-	if err != nil && !errors.IsNotFound(err) {
-		log.Printf("[Reconciler] Failed to delete resource %s from K8s: %v", resource.ID, err)
-		return
+	mapping, err := r.Resolver.ResolveKind(resource.Kind, resource.APIVersion)
+
+	if meta.IsNoMatchError(err) {
+		// A kind that no longer exists in the cluster (e.g. an uninstalled CRD) has no objects left to delete
+		log.Printf("[Reconciler] Kind %s no longer served for resource %s, skipping K8s delete", resource.Kind, resource.ID)
+	} else if err != nil {
+		log.Printf("[Reconciler] Failed to resolve GVR for resource %s: %v", resource.ID, err)
+		return false
+	} else if resourceClient, scopeErr := k8s.ResourceClient(r.DynamicClient, mapping, resource.Namespace); scopeErr != nil {
+		// A resource with the wrong scope could never be applied, so there is nothing to delete
+		log.Printf("[Reconciler] Scope mismatch for resource %s, skipping K8s delete: %v", resource.ID, scopeErr)
+	} else {
+		err = resourceClient.Delete(ctx, resource.Name, metav1.DeleteOptions{})
+
+		if err != nil && !errors.IsNotFound(err) {
+			log.Printf("[Reconciler] Failed to delete resource %s from K8s: %v", resource.ID, err)
+			return false
+		}
 	}
 
 	err = r.Client.HardDeleteResource(ctx, resource.ID)
 
 	if err != nil {
 		log.Printf("[Reconciler] Failed to hard delete resource %s: %v", resource.ID, err)
-		return
+		return false
 	}
 
 	log.Printf("[Reconciler] Successfully deleted resource %s", resource.ID)
+
+	return true
+}
+
+func (r *Reconciler) recordApplyError(ctx context.Context, resource *models.Resource, applyErr error) {
+	errMsg := applyErr.Error()
+
+	err := r.Client.UpsertAppliedState(ctx, resource.ID, &apiclient.UpsertAppliedStateRequest{
+		Status:              "error",
+		ErrorMessage:        &errMsg,
+		AttemptedGeneration: resource.Generation,
+	})
+
+	if err != nil {
+		log.Printf("[Reconciler] Failed to update applied_state for resource %s: %v", resource.ID, err)
+	}
 }
@@ -6,26 +6,43 @@ import (
 	"fmt"
 	"log"
 	"strconv"
-	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/targc/kontrol/pkg/apiclient"
 	"github.com/targc/kontrol/pkg/k8s"
+	"github.com/targc/kontrol/pkg/models"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
 )
 
 type Watcher struct {
-	Client        *apiclient.Client
-	ClusterID     string
-	DynamicClient dynamic.Interface
+	Client         *apiclient.Client
+	ClusterID      string
+	DynamicClient  dynamic.Interface
+	Resolver       *k8s.GVRResolver
+	ResyncInterval time.Duration
+	Namespaces     []string // empty watches all namespaces
+	RedactRules    []k8s.RedactRule
+
+	active atomic.Bool   // standby watchers keep their caches warm without reporting
+	resync chan struct{} // replays the caches after becoming active
 }
 
-func NewWatcher(client *apiclient.Client, clusterID, kubeconfig string) (*Watcher, error) {
+// informerKey identifies an informer by GVR and namespace ("" for cluster-wide)
+type informerKey struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+func NewWatcher(client *apiclient.Client, clusterID, kubeconfig string, resolver *k8s.GVRResolver, resyncInterval time.Duration, namespaces []string, redactRules []k8s.RedactRule) (*Watcher, error) {
 	config, err := k8s.BuildConfig(kubeconfig)
 
 	if err != nil {
@@ -38,85 +55,372 @@ func NewWatcher(client *apiclient.Client, clusterID, kubeconfig string) (*Watche
 		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
 	}
 
-	return &Watcher{
-		Client:        client,
-		ClusterID:     clusterID,
-		DynamicClient: dynamicClient,
-	}, nil
+	watcher := &Watcher{
+		Client:         client,
+		ClusterID:      clusterID,
+		DynamicClient:  dynamicClient,
+		Resolver:       resolver,
+		ResyncInterval: resyncInterval,
+		Namespaces:     namespaces,
+		RedactRules:    redactRules,
+		resync:         make(chan struct{}, 1),
+	}
+
+	watcher.active.Store(true)
+
+	return watcher, nil
+}
+
+// SetActive turns reporting of current states on or off. Standby workers keep their
+// informer caches warm without reporting; activating replays every cached object and
+// prunes stale current states, since events seen while standing by were not reported.
+func (w *Watcher) SetActive(active bool) {
+	if w.active.Swap(active) == active || !active {
+		return
+	}
+
+	select {
+	case w.resync <- struct{}{}:
+	default:
+	}
 }
 
+// Start runs a shared informer (list + watch) per supported GVR until ctx is cancelled.
+// Informers only list and watch objects carrying the kontrol/managed label, scoped to
+// the configured namespaces for namespaced kinds. They relist on watch expiry or 410 Gone
+// and replay every object on each resync, and stale current states are pruned after the
+// initial sync and on every resync, so k_resource_current_states converges even after
+// restarts or missed events.
 func (w *Watcher) Start(ctx context.Context) {
-	log.Println("[Watcher] Starting watches for cluster:", w.ClusterID)
+	log.Println("[Watcher] Starting informers for cluster:", w.ClusterID)
+
+	gvrs := append([]schema.GroupVersionResource{}, k8s.SupportedGVRs...)
+
+	for _, arg := range k8s.SupportedResourceArgs {
+		gvr, err := w.Resolver.ResolveResourceArg(arg)
+
+		if err != nil {
+			log.Printf("[Watcher] Skipping %s: %v", arg, err)
+			continue
+		}
+
+		gvrs = append(gvrs, gvr)
+	}
+
+	selector := k8s.ManagedSelector()
+	itemCtx := context.WithoutCancel(ctx)
+	factories := make(map[string]dynamicinformer.DynamicSharedInformerFactory)
+	informers := make(map[informerKey]cache.SharedIndexInformer)
 
-	var wg sync.WaitGroup
+	for _, gvr := range gvrs {
+		for _, namespace := range w.namespacesFor(gvr) {
+			factory, ok := factories[namespace]
 
-	for _, gvr := range k8s.SupportedGVRs {
-		wg.Add(1)
+			if !ok {
+				factory = dynamicinformer.NewFilteredDynamicSharedInformerFactory(w.DynamicClient, w.ResyncInterval, namespace, func(opts *metav1.ListOptions) {
+					opts.LabelSelector = selector
+				})
+				factories[namespace] = factory
+			}
+
+			informer := factory.ForResource(gvr).Informer()
+
+			// The event being handled at shutdown is still reported; Shutdown below waits for it
+			_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+				AddFunc: func(obj interface{}) {
+					w.handleUpsert(itemCtx, obj)
+				},
+				UpdateFunc: func(_, newObj interface{}) {
+					w.handleUpsert(itemCtx, newObj)
+				},
+				DeleteFunc: func(obj interface{}) {
+					w.handleDelete(itemCtx, obj)
+				},
+			})
+
+			if err != nil {
+				log.Printf("[Watcher] Failed to add event handler for %s: %v", gvr.Resource, err)
+				continue
+			}
+
+			informers[informerKey{gvr: gvr, namespace: namespace}] = informer
+
+			if namespace == "" {
+				log.Printf("[Watcher] Starting informer for %s", gvr.Resource)
+			} else {
+				log.Printf("[Watcher] Starting informer for %s in namespace %s", gvr.Resource, namespace)
+			}
+		}
+	}
+
+	for _, factory := range factories {
+		factory.Start(ctx.Done())
+	}
 
-		go func(gvr schema.GroupVersionResource) {
-			defer wg.Done()
-			w.watchGVR(ctx, gvr)
-		}(gvr)
+	for _, factory := range factories {
+		for gvr, synced := range factory.WaitForCacheSync(ctx.Done()) {
+			if !synced && ctx.Err() == nil {
+				log.Printf("[Watcher] Informer cache for %s failed to sync", gvr.Resource)
+			}
+		}
+	}
+
+	w.pruneLoop(ctx, informers)
+
+	for _, factory := range factories {
+		factory.Shutdown()
 	}
 
-	wg.Wait()
-	log.Println("[Watcher] All watches stopped")
+	log.Println("[Watcher] All informers stopped")
 }
 
-func (w *Watcher) watchGVR(ctx context.Context, gvr schema.GroupVersionResource) {
-	log.Printf("[Watcher] Starting watch for %s", gvr.Resource)
+// namespacesFor returns the namespaces to watch gvr in; "" means cluster-wide.
+// Cluster-scoped kinds are always watched cluster-wide.
+func (w *Watcher) namespacesFor(gvr schema.GroupVersionResource) []string {
+	if len(w.Namespaces) == 0 {
+		return []string{metav1.NamespaceAll}
+	}
+
+	mapping, err := w.Resolver.ResolveResource(gvr)
+
+	if err != nil {
+		log.Printf("[Watcher] Failed to resolve scope of %s, assuming namespaced: %v", gvr.Resource, err)
+		return w.Namespaces
+	}
+
+	if !k8s.IsNamespaced(mapping) {
+		return []string{metav1.NamespaceAll}
+	}
+
+	return w.Namespaces
+}
+
+// pruneLoop prunes stale current states once the caches are synced and again on every
+// resync interval, and replays the caches when the watcher becomes active, until ctx is
+// cancelled
+func (w *Watcher) pruneLoop(ctx context.Context, informers map[informerKey]cache.SharedIndexInformer) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	w.pruneCurrentStates(ctx, informers)
+
+	var tick <-chan time.Time
+
+	if w.ResyncInterval > 0 {
+		ticker := time.NewTicker(w.ResyncInterval)
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
 
 	for {
-		watcher, err := w.DynamicClient.Resource(gvr).Watch(ctx, metav1.ListOptions{})
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+			w.pruneCurrentStates(ctx, informers)
+		case <-w.resync:
+			w.replay(ctx, informers)
+			w.pruneCurrentStates(ctx, informers)
+		}
+	}
+}
 
-		if err != nil {
+// replay reports every cached object, e.g. after taking over from another worker
+func (w *Watcher) replay(ctx context.Context, informers map[informerKey]cache.SharedIndexInformer) {
+	log.Println("[Watcher] Replaying informer caches")
+
+	for _, informer := range informers {
+		for _, obj := range informer.GetStore().List() {
 			if ctx.Err() != nil {
 				return
 			}
 
-			log.Printf("[Watcher] Failed to watch %s: %v, retrying...", gvr.Resource, err)
-			time.Sleep(5 * time.Second)
-			continue
+			w.handleUpsert(ctx, obj)
 		}
+	}
+}
 
-		for event := range watcher.ResultChan() {
-			w.handleEvent(ctx, event)
-		}
+func (w *Watcher) handleUpsert(ctx context.Context, obj interface{}) {
+	if !w.active.Load() {
+		return
+	}
 
-		if ctx.Err() != nil {
-			return
-		}
+	u, ok := obj.(*unstructured.Unstructured)
 
-		log.Printf("[Watcher] Watch %s disconnected, reconnecting...", gvr.Resource)
+	if !ok {
+		return
 	}
+
+	resourceID, ok := managedResourceID(u)
+
+	if !ok {
+		return
+	}
+
+	w.upsertCurrentState(ctx, resourceID, u)
 }
 
-func (w *Watcher) handleEvent(ctx context.Context, event watch.Event) {
-	obj, ok := event.Object.(*unstructured.Unstructured)
+func (w *Watcher) handleDelete(ctx context.Context, obj interface{}) {
+	if !w.active.Load() {
+		return
+	}
+
+	// The informer hands out a tombstone when it missed the delete event and only noticed on relist
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	u, ok := obj.(*unstructured.Unstructured)
 
 	if !ok {
 		return
 	}
 
-	annotations := obj.GetAnnotations()
-	resourceIDStr := annotations["kontrol/resource-id"]
+	resourceID, ok := managedResourceID(u)
 
-	if resourceIDStr == "" {
+	if !ok {
 		return
 	}
 
+	w.deleteCurrentState(ctx, resourceID)
+}
+
+// managedResourceID returns the kontrol resource ID of an object applied by the reconciler
+func managedResourceID(obj *unstructured.Unstructured) (uuid.UUID, bool) {
+	resourceIDStr := obj.GetAnnotations()["kontrol/resource-id"]
+
+	if resourceIDStr == "" {
+		return uuid.Nil, false
+	}
+
 	resourceID, err := uuid.Parse(resourceIDStr)
 
 	if err != nil {
+		return uuid.Nil, false
+	}
+
+	return resourceID, true
+}
+
+// pruneCurrentStates removes current states whose object is no longer in the informer
+// cache, e.g. because it was deleted while the worker was not running. Objects missing
+// from the cache are looked up directly first, so objects applied before the
+// kontrol/managed label existed are labelled instead of reported deleted.
+func (w *Watcher) pruneCurrentStates(ctx context.Context, informers map[informerKey]cache.SharedIndexInformer) {
+	if !w.active.Load() {
 		return
 	}
 
-	switch event.Type {
-	case watch.Added, watch.Modified:
-		w.upsertCurrentState(ctx, resourceID, obj)
-	case watch.Deleted:
-		w.deleteCurrentState(ctx, resourceID)
+	after := uuid.Nil
+
+	for {
+		resources, err := w.Client.ListCurrentStateResources(ctx, after, 500)
+
+		if err != nil {
+			log.Printf("[Watcher] Failed to list current states for pruning: %v", err)
+			return
+		}
+
+		for _, resource := range resources {
+			mapping, err := w.Resolver.ResolveKind(resource.Kind, resource.APIVersion)
+
+			if err != nil {
+				continue
+			}
+
+			informer, ok := informers[informerKey{gvr: mapping.Resource, namespace: resource.Namespace}]
+
+			if !ok {
+				informer, ok = informers[informerKey{gvr: mapping.Resource}]
+			}
+
+			if !ok || !informer.HasSynced() {
+				continue
+			}
+
+			key := resource.Name
+
+			if resource.Namespace != "" {
+				key = resource.Namespace + "/" + resource.Name
+			}
+
+			item, exists, err := informer.GetStore().GetByKey(key)
+
+			if err != nil {
+				continue
+			}
+
+			if exists {
+				if u, ok := item.(*unstructured.Unstructured); ok {
+					if id, ok := managedResourceID(u); ok && id == resource.ID {
+						continue
+					}
+				}
+			}
+
+			adopted, err := w.adoptUnlabeled(ctx, mapping.Resource, &resource)
+
+			if err != nil {
+				log.Printf("[Watcher] Failed to check %s %s/%s before pruning: %v", resource.Kind, resource.Namespace, resource.Name, err)
+				continue
+			}
+
+			if adopted {
+				continue
+			}
+
+			w.deleteCurrentState(ctx, resource.ID)
+		}
+
+		if len(resources) < 500 {
+			return
+		}
+
+		after = resources[len(resources)-1].ID
+	}
+}
+
+// adoptUnlabeled labels the object of a resource when it exists without the
+// kontrol/managed label, which the reconciler only started setting later, so the
+// label-filtered informers pick it up. It reports whether the object exists and belongs
+// to the resource, in which case its current state must not be pruned.
+func (w *Watcher) adoptUnlabeled(ctx context.Context, gvr schema.GroupVersionResource, resource *models.Resource) (bool, error) {
+	client := w.DynamicClient.Resource(gvr).Namespace(resource.Namespace)
+
+	obj, err := client.Get(ctx, resource.Name, metav1.GetOptions{})
+
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if id, ok := managedResourceID(obj); !ok || id != resource.ID {
+		return false, nil
 	}
+
+	// Already labelled: the informer has not caught up yet
+	if obj.GetLabels()[k8s.ManagedLabel] == k8s.ManagedLabelValue {
+		return true, nil
+	}
+
+	patch := fmt.Sprintf(`{"metadata":{"labels":{%q:%q}}}`, k8s.ManagedLabel, k8s.ManagedLabelValue)
+
+	_, err = client.Patch(ctx, resource.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{
+		FieldManager: "kontrol",
+	})
+
+	if err != nil {
+		return false, fmt.Errorf("failed to label object: %w", err)
+	}
+
+	log.Printf("[Watcher] Labelled %s %s/%s, applied before %s was introduced", resource.Kind, resource.Namespace, resource.Name, k8s.ManagedLabel)
+
+	return true, nil
 }
 
 func (w *Watcher) upsertCurrentState(ctx context.Context, resourceID uuid.UUID, obj *unstructured.Unstructured) {
@@ -133,18 +437,45 @@ func (w *Watcher) upsertCurrentState(ctx context.Context, resourceID uuid.UUID,
 	generation, _ := strconv.Atoi(kontrolGeneration)
 	revision, _ := strconv.Atoi(kontrolRevision)
 
-	specBytes, err := json.Marshal(obj.Object["spec"])
+	// Redact before anything leaves the cluster; spec and status are taken from the redacted copy too
+	redacted := k8s.Redact(obj, w.RedactRules)
+
+	specBytes, err := json.Marshal(redacted.Object["spec"])
 
 	if err != nil {
 		log.Printf("[Watcher] Failed to marshal spec for resource %s: %v", resourceID, err)
 		return
 	}
 
+	objectBytes, err := json.Marshal(redacted.Object)
+
+	if err != nil {
+		log.Printf("[Watcher] Failed to marshal object for resource %s: %v", resourceID, err)
+		return
+	}
+
+	var statusBytes, conditionsBytes []byte
+
+	if status, ok := redacted.Object["status"].(map[string]interface{}); ok {
+		statusBytes, _ = json.Marshal(status)
+
+		if conditions, ok := status["conditions"]; ok {
+			conditionsBytes, _ = json.Marshal(conditions)
+		}
+	}
+
+	health, healthMessage := k8s.AssessHealth(obj)
+
 	err = w.Client.UpsertCurrentState(ctx, resourceID, &apiclient.UpsertCurrentStateRequest{
 		Spec:               specBytes,
+		Object:             objectBytes,
+		Status:             statusBytes,
+		Conditions:         conditionsBytes,
 		Generation:         generation,
 		Revision:           revision,
 		K8sResourceVersion: k8sResourceVersion,
+		Health:             health,
+		HealthMessage:      healthMessage,
 	})
 
 	if err != nil {
@@ -152,7 +483,7 @@ func (w *Watcher) upsertCurrentState(ctx context.Context, resourceID uuid.UUID,
 		return
 	}
 
-	log.Printf("[Watcher] Updated current_state for resource %s (gen=%d, rev=%d)", resourceID, generation, revision)
+	log.Printf("[Watcher] Updated current_state for resource %s (gen=%d, rev=%d, health=%s)", resourceID, generation, revision, health)
 }
 
 func (w *Watcher) deleteCurrentState(ctx context.Context, resourceID uuid.UUID) {
@@ -0,0 +1,97 @@
+package watcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/targc/kontrol/pkg/k8s"
+	"github.com/targc/kontrol/pkg/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var configMapGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+func configMap(name string, resourceID uuid.UUID, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetAnnotations(map[string]string{"kontrol/resource-id": resourceID.String()})
+	obj.SetLabels(labels)
+
+	return obj
+}
+
+// Objects applied before the kontrol/managed label existed must be labelled, not pruned
+func TestAdoptUnlabeled(t *testing.T) {
+	resourceID := uuid.Must(uuid.NewV7())
+	otherID := uuid.Must(uuid.NewV7())
+
+	tests := []struct {
+		name        string
+		objects     []runtime.Object
+		wantAdopted bool
+		wantLabel   bool
+	}{
+		{
+			name:        "unlabelled object from before the upgrade",
+			objects:     []runtime.Object{configMap("app", resourceID, nil)},
+			wantAdopted: true,
+			wantLabel:   true,
+		},
+		{
+			name:        "labelled object the informer has not seen yet",
+			objects:     []runtime.Object{configMap("app", resourceID, map[string]string{k8s.ManagedLabel: k8s.ManagedLabelValue})},
+			wantAdopted: true,
+			wantLabel:   true,
+		},
+		{
+			name:        "object deleted",
+			wantAdopted: false,
+		},
+		{
+			name:        "object belongs to another resource",
+			objects:     []runtime.Object{configMap("app", otherID, nil)},
+			wantAdopted: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), tt.objects...)
+			w := &Watcher{DynamicClient: client}
+
+			resource := &models.Resource{ID: resourceID, Namespace: "default", Kind: "ConfigMap", Name: "app", APIVersion: "v1"}
+
+			adopted, err := w.adoptUnlabeled(context.Background(), configMapGVR, resource)
+
+			if err != nil {
+				t.Fatalf("adoptUnlabeled() error = %v", err)
+			}
+
+			if adopted != tt.wantAdopted {
+				t.Fatalf("adoptUnlabeled() = %v, want %v", adopted, tt.wantAdopted)
+			}
+
+			if !tt.wantLabel {
+				return
+			}
+
+			obj, err := client.Resource(configMapGVR).Namespace("default").Get(context.Background(), "app", metav1.GetOptions{})
+
+			if err != nil {
+				t.Fatalf("failed to get object: %v", err)
+			}
+
+			if got := obj.GetLabels()[k8s.ManagedLabel]; got != k8s.ManagedLabelValue {
+				t.Errorf("label %s = %q, want %q", k8s.ManagedLabel, got, k8s.ManagedLabelValue)
+			}
+		})
+	}
+}
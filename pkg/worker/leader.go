@@ -0,0 +1,157 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// lead runs the reconciler and global syncer only while this replica holds the cluster's
+// leader lease, standing by and retrying every LeaseRenewInterval otherwise, until ctx is
+// cancelled. The lease keeps being renewed while in-flight work drains and is released
+// only afterwards, so the next leader neither waits for it to expire nor redoes an apply
+// that was about to be recorded. If renewals fail, the reconciler is fenced one renew
+// interval before the lease expires, so a drain never overlaps with a new leader.
+func (w *Worker) lead(ctx context.Context) {
+	for {
+		if !w.waitForLease(ctx) {
+			return
+		}
+
+		log.Printf("[Worker] Acquired leader lease for cluster %s as %s", w.ClusterID, w.ID)
+
+		leaderCtx, cancel := context.WithCancel(ctx)
+		renewCtx, stopRenewing := context.WithCancel(context.WithoutCancel(ctx))
+		renewed := make(chan struct{})
+
+		go func() {
+			defer close(renewed)
+			w.renewLease(renewCtx, cancel)
+		}()
+
+		w.watcher.SetActive(true)
+
+		var wg sync.WaitGroup
+
+		for _, start := range []func(context.Context){w.reconciler.Start, w.globalSyncer.Start} {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+				start(leaderCtx)
+			}()
+		}
+
+		wg.Wait()
+		w.watcher.SetActive(false)
+
+		cancel()
+		stopRenewing()
+		<-renewed
+		w.leaseValidUntil.Store(0)
+
+		if ctx.Err() != nil {
+			releaseCtx, cancelRelease := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			err := w.Client.ReleaseLease(releaseCtx, w.ID)
+			cancelRelease()
+
+			if err != nil {
+				log.Printf("[Worker] Failed to release leader lease: %v", err)
+				return
+			}
+
+			log.Println("[Worker] Released leader lease")
+			return
+		}
+
+		log.Printf("[Worker] Lost leader lease for cluster %s, standing by", w.ClusterID)
+	}
+}
+
+// waitForLease blocks until the leader lease is acquired, returning false if ctx is
+// cancelled first
+func (w *Worker) waitForLease(ctx context.Context) bool {
+	holder := ""
+
+	for {
+		requestedAt := time.Now()
+		lease, err := w.Client.AcquireLease(ctx, w.ID, w.LeaseDuration)
+
+		if err != nil {
+			log.Printf("[Worker] Failed to acquire leader lease: %v", err)
+		} else if lease.Acquired {
+			w.extendLease(requestedAt)
+			return true
+		} else if lease.HolderID != holder {
+			holder = lease.HolderID
+			log.Printf("[Worker] Standing by, leader lease held by %s", holder)
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(w.LeaseRenewInterval):
+		}
+	}
+}
+
+// renewLease renews the leader lease every LeaseRenewInterval until ctx is cancelled. It
+// calls lost when another replica took the lease over, or when renewals kept failing
+// until the lease is about to expire. Renewals continue after lost is called for a
+// failing API, so the lease still covers the drain if the API recovers.
+func (w *Worker) renewLease(ctx context.Context, lost context.CancelFunc) {
+	renewedAt := time.Now()
+	steppedDown := false
+
+	ticker := time.NewTicker(w.LeaseRenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		requestedAt := time.Now()
+		lease, err := w.Client.AcquireLease(ctx, w.ID, w.LeaseDuration)
+
+		if err == nil && lease.Acquired {
+			renewedAt = requestedAt
+			w.extendLease(requestedAt)
+			continue
+		}
+
+		if err == nil {
+			log.Printf("[Worker] Leader lease taken over by %s", lease.HolderID)
+			w.leaseValidUntil.Store(0)
+			lost()
+			return
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Printf("[Worker] Failed to renew leader lease: %v", err)
+
+		if !steppedDown && time.Since(renewedAt) >= w.LeaseDuration-w.LeaseRenewInterval {
+			log.Println("[Worker] Leader lease about to expire, stepping down")
+			steppedDown = true
+			lost()
+		}
+	}
+}
+
+// extendLease records a successful acquire or renewal requested at the given time. The
+// lease is treated as lost one renew interval before it expires, leaving a margin for
+// clock drift and for an apply that passed the fence just before.
+func (w *Worker) extendLease(requestedAt time.Time) {
+	w.leaseValidUntil.Store(requestedAt.Add(w.LeaseDuration - w.LeaseRenewInterval).UnixNano())
+}
+
+// holdsLease reports whether the leader lease is surely still held by this replica
+func (w *Worker) holdsLease() bool {
+	return time.Now().UnixNano() < w.leaseValidUntil.Load()
+}
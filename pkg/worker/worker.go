@@ -4,24 +4,47 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/targc/kontrol/pkg/apiclient"
+	"github.com/targc/kontrol/pkg/config"
 	"github.com/targc/kontrol/pkg/global_syncer"
+	"github.com/targc/kontrol/pkg/k8s"
 	"github.com/targc/kontrol/pkg/reconciler"
 	"github.com/targc/kontrol/pkg/watcher"
 )
 
+// eventStreamRetryDelay is the delay before reconnecting a broken change event stream
+const eventStreamRetryDelay = 5 * time.Second
+
 type Worker struct {
 	Client       *apiclient.Client
 	ClusterID    string
 	Kubeconfig   string
+	DrainTimeout time.Duration // how long Start waits for in-flight work after being stopped
+	ID           string        // identity of this replica in the leader lease
+
+	LeaderElection     bool // reconcile only while holding the cluster's leader lease
+	LeaseDuration      time.Duration
+	LeaseRenewInterval time.Duration
+
+	leaseValidUntil atomic.Int64 // unix nanoseconds until which the leader lease is surely held
+
+	resolver     *k8s.GVRResolver
 	watcher      *watcher.Watcher
 	reconciler   *reconciler.Reconciler
 	globalSyncer *global_syncer.GlobalSyncer
 	cancel       context.CancelFunc
 }
 
-func NewWorker(ctx context.Context, client *apiclient.Client, clusterID, kubeconfig string) (*Worker, error) {
+func NewWorker(ctx context.Context, client *apiclient.Client, cfg *config.WorkerConfig) (*Worker, error) {
+	clusterID := cfg.ClusterID
+	kubeconfig := cfg.Kubeconfig
+
 	// Register cluster with API
 	err := client.RegisterCluster(ctx)
 
@@ -31,44 +54,153 @@ func NewWorker(ctx context.Context, client *apiclient.Client, clusterID, kubecon
 
 	log.Printf("[Worker] Registered cluster: %s", clusterID)
 
-	w, err := watcher.NewWatcher(client, clusterID, kubeconfig)
+	restConfig, err := k8s.BuildConfig(kubeconfig)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kubernetes config: %w", err)
+	}
+
+	resolver, err := k8s.NewGVRResolver(restConfig, cfg.DiscoveryRefreshInterval)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gvr resolver: %w", err)
+	}
+
+	w, err := watcher.NewWatcher(client, clusterID, kubeconfig, resolver, cfg.WatchResyncInterval, k8s.ParseNamespaces(cfg.WatchNamespaces), k8s.ParseRedactRules(cfg.WatchRedactFields))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create watcher: %w", err)
 	}
 
-	r, err := reconciler.NewReconciler(client, clusterID, kubeconfig)
+	r, err := reconciler.NewReconciler(client, clusterID, kubeconfig, resolver, cfg.ReconcileConcurrency, cfg.PollInterval, cfg.StreamPollInterval)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create reconciler: %w", err)
 	}
 
-	gs := global_syncer.NewGlobalSyncer(client, clusterID)
+	gs := global_syncer.NewGlobalSyncer(client, clusterID, cfg.PollInterval, cfg.StreamPollInterval)
 
-	return &Worker{
+	workerID := cfg.WorkerID
+
+	if workerID == "" {
+		hostname, _ := os.Hostname()
+		workerID = hostname + "-" + uuid.NewString()[:8]
+	}
+
+	// Standbys only report current states once they take over
+	if cfg.LeaderElection {
+		w.SetActive(false)
+	}
+
+	worker := &Worker{
 		Client:       client,
 		ClusterID:    clusterID,
 		Kubeconfig:   kubeconfig,
+		DrainTimeout: cfg.ShutdownDrainTimeout,
+		ID:           workerID,
+
+		LeaderElection:     cfg.LeaderElection,
+		LeaseDuration:      cfg.LeaseDuration,
+		LeaseRenewInterval: cfg.LeaseRenewInterval,
+
+		resolver:     resolver,
 		watcher:      w,
 		reconciler:   r,
 		globalSyncer: gs,
-	}, nil
+	}
+
+	if cfg.LeaderElection {
+		r.Fence = worker.holdsLease
+	}
+
+	return worker, nil
 }
 
+// Start runs the worker until ctx is cancelled or Stop is called. It then waits up to
+// DrainTimeout for the watcher, reconciler and global syncer to finish the item they are
+// working on, and returns an error if they did not stop in time.
 func (w *Worker) Start(ctx context.Context) error {
 	log.Printf("[Worker] Starting for cluster: %s", w.ClusterID)
 
 	ctx, cancel := context.WithCancel(ctx)
 	w.cancel = cancel
 
-	go w.watcher.Start(ctx)
-	go w.reconciler.Start(ctx)
-	go w.globalSyncer.Start(ctx)
+	var wg sync.WaitGroup
+
+	run := func(start func(context.Context)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			start(ctx)
+		}()
+	}
+
+	go w.resolver.Start(ctx)
+	go w.streamEvents(ctx)
+
+	run(w.watcher.Start)
+
+	if w.LeaderElection {
+		run(w.lead)
+	} else {
+		run(w.reconciler.Start)
+		run(w.globalSyncer.Start)
+	}
 
 	<-ctx.Done()
-	log.Println("[Worker] Stopped")
+	log.Printf("[Worker] Draining in-flight work (timeout %s)", w.DrainTimeout)
+
+	drained := make(chan struct{})
+
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
 
-	return nil
+	select {
+	case <-drained:
+		log.Println("[Worker] Stopped cleanly")
+		return nil
+	case <-time.After(w.DrainTimeout):
+		return fmt.Errorf("in-flight work did not finish within the drain timeout of %s", w.DrainTimeout)
+	}
+}
+
+// streamEvents wakes the reconciler and global syncer on change events pushed by the API,
+// reconnecting until ctx is cancelled. Both loops keep polling while the stream is down.
+func (w *Worker) streamEvents(ctx context.Context) {
+	for {
+		err := w.Client.StreamEvents(ctx, func() {
+			log.Println("[Worker] Change event stream connected")
+
+			// Catch up on changes made while the stream was down
+			w.reconciler.Wake()
+			w.globalSyncer.Wake()
+		}, func(event apiclient.ChangeEvent) {
+			switch event.Type {
+			case apiclient.ChangeEventResource:
+				w.reconciler.Wake()
+			case apiclient.ChangeEventGlobalResource:
+				w.globalSyncer.Wake()
+			case apiclient.ChangeEventResync:
+				w.reconciler.Wake()
+				w.globalSyncer.Wake()
+			}
+		})
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Printf("[Worker] Change event stream disconnected, retrying in %s: %v", eventStreamRetryDelay, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventStreamRetryDelay):
+		}
+	}
 }
 
 func (w *Worker) Stop() {